@@ -0,0 +1,178 @@
+package approval
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const Filename = "APPROVAL.md"
+
+// Files are the Gate 2 artifacts a human approves, in approval order.
+var Files = []string{"PLAN.md", "TASKS.md"}
+
+var (
+	recordPattern   = regexp.MustCompile(`^- (\S+) approved by (.+) at (\S+) \(sha256:([0-9a-f]{64})\)$`)
+	checkedPattern  = regexp.MustCompile(`^( ?[-*+]\s+)\[[xX]\]`)
+	approvalsHeader = "# Approvals\n\nRecorded by `spire approve`. Editing an approved file invalidates its approval.\n\n"
+)
+
+type Record struct {
+	File     string
+	Approver string
+	At       time.Time
+	Hash     string
+}
+
+type State int
+
+const (
+	// Missing means the file itself does not exist yet.
+	Missing State = iota
+	Unapproved
+	Approved
+	// Stale means the file changed after it was approved.
+	Stale
+)
+
+type FileStatus struct {
+	File   string
+	State  State
+	Record *Record
+}
+
+// Path returns where a feature's approval record lives.
+func Path(projectRoot string, slug string) string {
+	return filepath.Join(projectRoot, "changes", slug, Filename)
+}
+
+// Load reads a feature's approval records, keyed by file name. It returns an
+// empty map when nothing has been approved.
+func Load(projectRoot string, slug string) (map[string]Record, error) {
+	path := Path(projectRoot, slug)
+	records := map[string]Record{}
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return records, nil
+		}
+		return nil, fmt.Errorf("open approval record %q: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		match := recordPattern.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, match[3])
+		if err != nil {
+			return nil, fmt.Errorf("parse approval time %q in %s: %w", match[3], path, err)
+		}
+		records[match[1]] = Record{File: match[1], Approver: match[2], At: at, Hash: match[4]}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scan approval record %q: %w", path, err)
+	}
+	return records, nil
+}
+
+// Hash fingerprints an approvable file. Task checkboxes are unticked first,
+// so checking off tasks does not invalidate an approved TASKS.md.
+func Hash(name string, content []byte) string {
+	if name == "TASKS.md" {
+		lines := strings.Split(string(content), "\n")
+		for i, line := range lines {
+			lines[i] = checkedPattern.ReplaceAllString(line, "$1[ ]")
+		}
+		content = []byte(strings.Join(lines, "\n"))
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// Approve records approver's sign-off on files at now, replacing earlier
+// approvals of the same files.
+func Approve(projectRoot string, slug string, files []string, approver string, now time.Time) ([]Record, error) {
+	records, err := Load(projectRoot, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	var approved []Record
+	for _, name := range files {
+		path := filepath.Join(projectRoot, "changes", slug, name)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("%s does not exist yet", filepath.ToSlash(filepath.Join("changes", slug, name)))
+			}
+			return nil, fmt.Errorf("read %q: %w", path, err)
+		}
+		record := Record{File: name, Approver: approver, At: now.UTC().Truncate(time.Second), Hash: Hash(name, content)}
+		records[name] = record
+		approved = append(approved, record)
+	}
+
+	if err := write(Path(projectRoot, slug), records); err != nil {
+		return nil, err
+	}
+	return approved, nil
+}
+
+// Status reports, for each of Files, whether it exists and carries a current
+// approval.
+func Status(projectRoot string, slug string) ([]FileStatus, error) {
+	records, err := Load(projectRoot, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]FileStatus, 0, len(Files))
+	for _, name := range Files {
+		status := FileStatus{File: name}
+		content, err := os.ReadFile(filepath.Join(projectRoot, "changes", slug, name))
+		switch {
+		case os.IsNotExist(err):
+			status.State = Missing
+		case err != nil:
+			return nil, fmt.Errorf("read %s: %w", name, err)
+		default:
+			record, ok := records[name]
+			switch {
+			case !ok:
+				status.State = Unapproved
+			case record.Hash != Hash(name, content):
+				status.State = Stale
+				status.Record = &record
+			default:
+				status.State = Approved
+				status.Record = &record
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func write(path string, records map[string]Record) error {
+	var body strings.Builder
+	body.WriteString(approvalsHeader)
+	for _, name := range Files {
+		if record, ok := records[name]; ok {
+			fmt.Fprintf(&body, "- %s approved by %s at %s (sha256:%s)\n", record.File, record.Approver, record.At.Format(time.RFC3339), record.Hash)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(body.String()), 0o644); err != nil {
+		return fmt.Errorf("write approval record %q: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package approval
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApproveRecordsAndDetectsEdits(t *testing.T) {
+	projectRoot := t.TempDir()
+	dir := filepath.Join(projectRoot, "changes", "001-x")
+	writeFile(t, filepath.Join(dir, "PLAN.md"), "# Plan\n")
+	writeFile(t, filepath.Join(dir, "TASKS.md"), "- [ ] T1: parse\n- [ ] T2: retry\n")
+
+	at := time.Date(2026, 10, 19, 14, 2, 0, 0, time.UTC)
+	if _, err := Approve(projectRoot, "001-x", Files, "Ada Lovelace <ada@example.com>", at); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+
+	data, err := os.ReadFile(Path(projectRoot, "001-x"))
+	if err != nil {
+		t.Fatalf("read record: %v", err)
+	}
+	if want := "- PLAN.md approved by Ada Lovelace <ada@example.com> at 2026-10-19T14:02:00Z (sha256:" + Hash("PLAN.md", []byte("# Plan\n")) + ")\n"; !strings.Contains(string(data), want) {
+		t.Fatalf("record missing %q:\n%s", want, data)
+	}
+
+	// Ticking a task keeps TASKS.md approved; editing its text does not.
+	writeFile(t, filepath.Join(dir, "TASKS.md"), "- [x] T1: parse\n- [ ] T2: retry\n")
+	assertStates(t, projectRoot, Approved, Approved)
+	writeFile(t, filepath.Join(dir, "TASKS.md"), "- [x] T1: parse\n- [ ] T2: retry on 5xx\n")
+	assertStates(t, projectRoot, Approved, Stale)
+
+	records, err := Load(projectRoot, "001-x")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if record := records["TASKS.md"]; record.Approver != "Ada Lovelace <ada@example.com>" || !record.At.Equal(at) {
+		t.Fatalf("record: %+v", record)
+	}
+}
+
+func TestApproveRefusesMissingFile(t *testing.T) {
+	projectRoot := t.TempDir()
+	writeFile(t, filepath.Join(projectRoot, "changes", "001-x", "PLAN.md"), "# Plan\n")
+
+	if _, err := Approve(projectRoot, "001-x", Files, "Ada", time.Now()); err == nil || !strings.Contains(err.Error(), "changes/001-x/TASKS.md does not exist yet") {
+		t.Fatalf("Approve error: %v", err)
+	}
+	assertStates(t, projectRoot, Unapproved, Missing)
+}
+
+func assertStates(t *testing.T, projectRoot string, plan State, tasks State) {
+	t.Helper()
+	statuses, err := Status(projectRoot, "001-x")
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if statuses[0].State != plan || statuses[1].State != tasks {
+		t.Fatalf("states: got %v/%v, want %v/%v", statuses[0].State, statuses[1].State, plan, tasks)
+	}
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
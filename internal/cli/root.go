@@ -1,81 +1,82 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"opencode-spire/internal/commands"
 )
 
 var Version = "dev"
 
+// ExitInterrupted is the conventional shell exit code for a command stopped by
+// SIGINT (128 + 2).
+const ExitInterrupted = 130
+
+// Execute runs the command named by args under a context that is cancelled on
+// SIGINT or SIGTERM. A second signal falls through to the default handler and
+// kills the process.
 func Execute(args []string, stdout io.Writer, stderr io.Writer) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	return execute(ctx, args, stdout, stderr)
+}
+
+func execute(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	code := dispatch(ctx, args, stdout, stderr)
+	if code != 0 && ctx.Err() != nil {
+		fmt.Fprintln(stderr, "interrupted")
+		return ExitInterrupted
+	}
+	return code
+}
+
+func dispatch(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
 	if len(args) == 0 {
-		printHelp(stdout)
+		commands.PrintHelp(stdout)
 		return 0
 	}
 
 	switch args[0] {
-	case "--help", "-h", "help":
-		printHelp(stdout)
+	case "--help", "-h":
+		commands.PrintHelp(stdout)
 		return 0
 	case "--version", "-v":
 		fmt.Fprintf(stdout, "spire %s\n", Version)
 		return 0
-	case "init":
-		cwd, err := os.Getwd()
-		if err != nil {
-			fmt.Fprintf(stderr, "failed to determine working directory: %v\n", err)
-			return 1
-		}
-		return commands.RunInit(args[1:], cwd, stdout, stderr)
-	case "update":
-		cwd, err := os.Getwd()
-		if err != nil {
-			fmt.Fprintf(stderr, "failed to determine working directory: %v\n", err)
-			return 1
-		}
-		return commands.RunUpdate(args[1:], cwd, os.Stdin, isInteractiveStdin(os.Stdin), stdout, stderr)
-	case "new":
-		cwd, err := os.Getwd()
-		if err != nil {
-			fmt.Fprintf(stderr, "failed to determine working directory: %v\n", err)
-			return 1
-		}
-		return commands.RunNew(args[1:], cwd, os.Stdin, stdout, stderr)
-	case "status":
-		cwd, err := os.Getwd()
-		if err != nil {
-			fmt.Fprintf(stderr, "failed to determine working directory: %v\n", err)
-			return 1
-		}
-		return commands.RunStatus(args[1:], cwd, stdout, stderr)
-	case "upgrade":
-		return commands.RunUpgrade(args[1:], Version, stdout, stderr)
-	default:
+	}
+
+	command, ok := commands.Lookup(args[0])
+	if !ok {
 		fmt.Fprintf(stderr, "unknown command: %s\n\n", args[0])
-		printHelp(stderr)
+		commands.PrintHelp(stderr)
+		return 1
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to determine working directory: %v\n", err)
 		return 1
 	}
-}
 
-func printHelp(w io.Writer) {
-	fmt.Fprintln(w, "spire - SDD methodology CLI")
-	fmt.Fprintln(w)
-	fmt.Fprintln(w, "Usage:")
-	fmt.Fprintln(w, "  spire <command>")
-	fmt.Fprintln(w)
-	fmt.Fprintln(w, "Commands:")
-	fmt.Fprintln(w, "  init      Initialize project methodology")
-	fmt.Fprintln(w, "  update    Update local methodology")
-	fmt.Fprintln(w, "  new       Create a new feature spec")
-	fmt.Fprintln(w, "  status    Show feature status table")
-	fmt.Fprintln(w, "  upgrade   Upgrade spire executable")
-	fmt.Fprintln(w)
-	fmt.Fprintln(w, "Flags:")
-	fmt.Fprintln(w, "  -h, --help       Show help")
-	fmt.Fprintln(w, "  -v, --version    Show version")
+	return command.Run(ctx, commands.Invocation{
+		Args:        args[1:],
+		Dir:         cwd,
+		Version:     Version,
+		Stdin:       os.Stdin,
+		Interactive: isInteractiveStdin(os.Stdin),
+		Stdout:      stdout,
+		Stderr:      stderr,
+	})
 }
 
 func isInteractiveStdin(file *os.File) bool {
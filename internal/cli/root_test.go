@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestExecuteReportsInterruptedCommand(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("SPIRE_CACHE_DIR", t.TempDir())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := execute(ctx, []string{"init"}, &stdout, &stderr)
+
+	if exitCode != ExitInterrupted {
+		t.Fatalf("exit code: got %d, want %d (stderr=%q)", exitCode, ExitInterrupted, stderr.String())
+	}
+	if !strings.HasSuffix(stderr.String(), "interrupted\n") {
+		t.Fatalf("stderr: %q", stderr.String())
+	}
+}
+
+func TestExecuteKeepsSuccessAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var stdout bytes.Buffer
+	exitCode := execute(ctx, []string{"--version"}, &stdout, &bytes.Buffer{})
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, want 0", exitCode)
+	}
+	if !strings.Contains(stdout.String(), "spire dev") {
+		t.Fatalf("stdout: %q", stdout.String())
+	}
+}
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"opencode-spire/internal/approval"
+)
+
+type approveFlags struct {
+	plan  *bool
+	tasks *bool
+}
+
+func declareApproveFlags(flags *flag.FlagSet) approveFlags {
+	return approveFlags{
+		plan:  flags.Bool("plan", false, "approve only PLAN.md"),
+		tasks: flags.Bool("tasks", false, "approve only TASKS.md"),
+	}
+}
+
+func RunApprove(ctx context.Context, args []string, projectRoot string, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("approve", stderr)
+	options := declareApproveFlags(flags)
+	if done, code := parseFlags(flags, args, stdout); done {
+		return code
+	}
+	if flags.NArg() != 1 || (*options.plan && *options.tasks) {
+		printUsage(stderr, "approve")
+		return 1
+	}
+
+	files := approval.Files
+	switch {
+	case *options.plan:
+		files = []string{"PLAN.md"}
+	case *options.tasks:
+		files = []string{"TASKS.md"}
+	}
+
+	feature, err := resolveFeature(projectRoot, flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+
+	approver, err := gitApprover(ctx, projectRoot)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to identify approver: %v\n", err)
+		return 1
+	}
+
+	records, err := approval.Approve(projectRoot, feature.Slug, files, approver, time.Now())
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to approve %s: %v\n", feature.Slug, err)
+		return 1
+	}
+	for _, record := range records {
+		fmt.Fprintf(stdout, "approved %s for %s as %s (sha256:%s)\n", record.File, feature.Slug, record.Approver, record.Hash[:12])
+	}
+	return 0
+}
+
+// gitApprover names the approver as `Name <email>` from git config.
+func gitApprover(ctx context.Context, projectRoot string) (string, error) {
+	repo, err := openRepo(ctx, projectRoot)
+	if err != nil {
+		return "", err
+	}
+	name, err := repo.Config(ctx, "user.name")
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		return "", fmt.Errorf("git config user.name is not set")
+	}
+	email, err := repo.Config(ctx, "user.email")
+	if err != nil {
+		return "", err
+	}
+	if email == "" {
+		return name, nil
+	}
+	return fmt.Sprintf("%s <%s>", name, email), nil
+}
@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"opencode-spire/internal/git"
+)
+
+func TestRunApproveRecordsGitIdentity(t *testing.T) {
+	projectRoot := createTaskFeature(t)
+	writeFile(t, filepath.Join(projectRoot, "changes", "003-retry", "PLAN.md"), "# Plan\n")
+
+	original := openRepo
+	t.Cleanup(func() { openRepo = original })
+	openRepo = func(ctx context.Context, dir string) (git.Repo, error) {
+		return &git.Fake{Settings: map[string]string{"user.name": "Ada Lovelace", "user.email": "ada@example.com"}}, nil
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if code := RunApprove(context.Background(), []string{"003", "--plan"}, projectRoot, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", code, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "approved PLAN.md for 003-retry as Ada Lovelace <ada@example.com> (sha256:") {
+		t.Fatalf("stdout: %q", stdout.String())
+	}
+	assertFileContains(t, filepath.Join(projectRoot, "changes", "003-retry", "APPROVAL.md"), "- PLAN.md approved by Ada Lovelace <ada@example.com> at ")
+
+	openRepo = func(ctx context.Context, dir string) (git.Repo, error) { return &git.Fake{}, nil }
+	stderr.Reset()
+	if code := RunApprove(context.Background(), []string{"003"}, projectRoot, &stdout, &stderr); code != 1 {
+		t.Fatalf("exit code without git identity: got %d, want 1", code)
+	}
+	if !strings.Contains(stderr.String(), "git config user.name is not set") {
+		t.Fatalf("stderr: %q", stderr.String())
+	}
+}
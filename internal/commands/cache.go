@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"opencode-spire/internal/methodology"
+)
+
+func RunCache(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stderr, "cache")
+		return 1
+	}
+
+	switch args[0] {
+	case "-h", "--help", "-help":
+		printCommandHelp(stdout, "cache")
+		return 0
+	case "list":
+		return runCacheList(args[1:], stdout, stderr)
+	case "prune":
+		return runCachePrune(args[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "unknown cache command: %s\n", args[0])
+		printUsage(stderr, "cache")
+		return 1
+	}
+}
+
+func runCacheList(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) > 0 {
+		fmt.Fprintln(stderr, "usage: spire cache list")
+		return 1
+	}
+
+	entries, err := methodology.ListCacheEntries(stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to list cache: %v\n", err)
+		return 1
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintln(stdout, "cache is empty")
+		return 0
+	}
+
+	writer := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "REPOSITORY\tREF\tDIGEST\tFETCHED")
+	for _, entry := range entries {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", entry.Repository, entry.Ref, shortDigest(entry.Digest), entry.FetchedAt)
+	}
+	writer.Flush()
+
+	return 0
+}
+
+func declareCachePruneFlags(flags *flag.FlagSet) *bool {
+	return flags.Bool("all", false, "with prune, remove every cached payload, including the newest")
+}
+
+func runCachePrune(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("cache prune", stderr)
+	all := declareCachePruneFlags(flags)
+	if done, code := parseFlags(flags, args, stdout); done {
+		return code
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintln(stderr, "usage: spire cache prune [--all]")
+		return 1
+	}
+
+	removed, err := methodology.PruneCache(*all, stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to prune cache: %v\n", err)
+		return 1
+	}
+
+	for _, entry := range removed {
+		fmt.Fprintf(stdout, "removed: %s@%s %s\n", entry.Repository, entry.Ref, shortDigest(entry.Digest))
+	}
+	fmt.Fprintf(stdout, "pruned %d cached payload(s)\n", len(removed))
+
+	return 0
+}
+
+func shortDigest(digest string) string {
+	if len(digest) > 12 {
+		return digest[:12]
+	}
+	return digest
+}
@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"opencode-spire/internal/methodology"
+)
+
+func TestRunUpdateOfflineUsesCachedPayload(t *testing.T) {
+	source := createMethodologySource(t)
+	t.Setenv("SPIRE_CACHE_DIR", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(buildMethodologyTarball(t, source))
+	}))
+	restore := methodology.SetCanonicalSourceForTesting("niparis/spire", "main", server.URL+"/tarball.tar.gz")
+	t.Cleanup(restore)
+
+	projectRoot := t.TempDir()
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+	server.Close()
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunUpdate(context.Background(), []string{"--offline"}, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "updated .methodology") {
+		t.Fatalf("stdout: %q", stdout.String())
+	}
+	assertFileContains(t, filepath.Join(projectRoot, ".methodology", ".spire-source.json"), "\"digest\"")
+}
+
+func TestRunInitOfflineWithoutCacheFallsBackToEmbedded(t *testing.T) {
+	configureCanonicalSourceFromDir(t, createMethodologySource(t))
+	t.Cleanup(methodology.SetEmbeddedPayloadForTesting(os.DirFS(createMethodologySource(t)), "1.2.3"))
+
+	var stderr bytes.Buffer
+	exitCode := RunInit(context.Background(), []string{"--offline"}, t.TempDir(), &bytes.Buffer{}, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "no cached methodology payload for niparis/spire@main") {
+		t.Fatalf("stderr: %q", stderr.String())
+	}
+}
+
+func TestMethodologyCacheRevalidatesWithETag(t *testing.T) {
+	source := createMethodologySource(t)
+	t.Setenv("SPIRE_CACHE_DIR", t.TempDir())
+
+	var notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write(buildMethodologyTarball(t, source))
+	}))
+	t.Cleanup(server.Close)
+	restore := methodology.SetCanonicalSourceForTesting("niparis/spire", "main", server.URL+"/tarball.tar.gz")
+	t.Cleanup(restore)
+
+	projectRoot := t.TempDir()
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+	if code := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("update failed with code %d", code)
+	}
+
+	if got := notModified.Load(); got != 1 {
+		t.Fatalf("304 responses: got %d, want 1", got)
+	}
+}
+
+func TestRunCacheListAndPrune(t *testing.T) {
+	source := createMethodologySource(t)
+	configureCanonicalSourceFromDir(t, source)
+
+	projectRoot := t.TempDir()
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+	writeFile(t, filepath.Join(source, "agents", "SPIRE.md"), "# SPIRE v2\n")
+	if code := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("update failed with code %d", code)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if code := RunCache(context.Background(), []string{"list"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("cache list failed: %q", stderr.String())
+	}
+	if got := strings.Count(stdout.String(), "niparis/spire"); got != 2 {
+		t.Fatalf("cache list entries: got %d, output=%q", got, stdout.String())
+	}
+
+	stdout.Reset()
+	if code := RunCache(context.Background(), []string{"prune"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("cache prune failed: %q", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "pruned 1 cached payload(s)") {
+		t.Fatalf("stdout: %q", stdout.String())
+	}
+
+	if code := RunUpdate(context.Background(), []string{"--offline"}, projectRoot, strings.NewReader(""), false, &bytes.Buffer{}, &stderr); code != 0 {
+		t.Fatalf("offline update after prune failed: %q", stderr.String())
+	}
+	assertFileContains(t, filepath.Join(projectRoot, ".methodology", "agents", "SPIRE.md"), "SPIRE v2")
+}
+
+func TestRunInitDownloadsWithoutCacheWhenCacheDirUnusable(t *testing.T) {
+	configureCanonicalSourceFromDir(t, createMethodologySource(t))
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	writeFile(t, blocker, "not a directory\n")
+	t.Setenv("SPIRE_CACHE_DIR", filepath.Join(blocker, "cache"))
+
+	projectRoot := t.TempDir()
+	var stderr bytes.Buffer
+	exitCode := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "warning: downloading without the cache") {
+		t.Fatalf("stderr: %q", stderr.String())
+	}
+	assertFileContains(t, filepath.Join(projectRoot, ".methodology", ".spire-source.json"), "\"digest\"")
+}
+
+func TestRunCacheSkipsCorruptEntry(t *testing.T) {
+	configureCanonicalSourceFromDir(t, createMethodologySource(t))
+
+	projectRoot := t.TempDir()
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+	corrupt := filepath.Join(os.Getenv("SPIRE_CACHE_DIR"), "methodology", "niparis%2Fspire", "main", "corrupt")
+	writeFile(t, filepath.Join(corrupt, "entry.json"), "{")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if code := RunCache(context.Background(), []string{"list"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("cache list failed: %q", stderr.String())
+	}
+	if got := strings.Count(stdout.String(), "niparis/spire"); got != 1 {
+		t.Fatalf("cache list entries: got %d, output=%q", got, stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "warning: skipping cached payload") {
+		t.Fatalf("stderr: %q", stderr.String())
+	}
+
+	stderr.Reset()
+	if code := RunUpdate(context.Background(), []string{"--offline"}, projectRoot, strings.NewReader(""), false, &bytes.Buffer{}, &stderr); code != 0 {
+		t.Fatalf("offline update with corrupt entry failed: %q", stderr.String())
+	}
+}
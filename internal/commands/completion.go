@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var completionShells = []string{"bash", "zsh", "fish"}
+
+func RunCompletion(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("completion", stderr)
+	if done, code := parseFlags(flags, args, stdout); done {
+		return code
+	}
+	if flags.NArg() != 1 {
+		printUsage(stderr, "completion")
+		return 1
+	}
+
+	switch flags.Arg(0) {
+	case "bash":
+		writeBashCompletion(stdout, Registry())
+	case "zsh":
+		writeZshCompletion(stdout, Registry())
+	case "fish":
+		writeFishCompletion(stdout, Registry())
+	default:
+		fmt.Fprintf(stderr, "unsupported shell %q (want %s)\n", flags.Arg(0), strings.Join(completionShells, ", "))
+		return 1
+	}
+	return 0
+}
+
+// completionWords lists what may follow the command name: its positional
+// words, then its flags.
+func completionWords(command Command) []string {
+	words := append([]string{}, command.Words()...)
+	command.FlagSet().VisitAll(func(f *flag.Flag) {
+		words = append(words, "--"+f.Name)
+	})
+	return words
+}
+
+func writeBashCompletion(w io.Writer, registry []Command) {
+	fmt.Fprintln(w, "# bash completion for spire")
+	fmt.Fprintln(w, "_spire() {")
+	fmt.Fprintln(w, `	local cur="${COMP_WORDS[COMP_CWORD]}"`)
+	fmt.Fprintln(w, `	if [ "$COMP_CWORD" -eq 1 ]; then`)
+	fmt.Fprintf(w, "\t\tCOMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(commandNames(), " "))
+	fmt.Fprintln(w, "\t\treturn")
+	fmt.Fprintln(w, "\tfi")
+	fmt.Fprintln(w, `	local words=""`)
+	fmt.Fprintln(w, `	case "${COMP_WORDS[1]}" in`)
+	for _, command := range registry {
+		if words := completionWords(command); len(words) > 0 {
+			fmt.Fprintf(w, "\t\t%s) words=%q ;;\n", command.Name, strings.Join(words, " "))
+		}
+	}
+	fmt.Fprintln(w, "\tesac")
+	fmt.Fprintln(w, `	COMPREPLY=($(compgen -W "$words" -- "$cur"))`)
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, "complete -F _spire spire")
+}
+
+func writeZshCompletion(w io.Writer, registry []Command) {
+	fmt.Fprintln(w, "#compdef spire")
+	fmt.Fprintln(w, "_spire() {")
+	fmt.Fprintln(w, "\tlocal -a subcommands")
+	fmt.Fprintln(w, "\tsubcommands=(")
+	for _, command := range registry {
+		fmt.Fprintf(w, "\t\t%s\n", zshQuote(command.Name+":"+command.Summary))
+	}
+	fmt.Fprintln(w, "\t)")
+	fmt.Fprintln(w, "\tif (( CURRENT == 2 )); then")
+	fmt.Fprintln(w, "\t\t_describe 'command' subcommands")
+	fmt.Fprintln(w, "\t\treturn")
+	fmt.Fprintln(w, "\tfi")
+	fmt.Fprintln(w, `	case "$words[2]" in`)
+	for _, command := range registry {
+		if words := completionWords(command); len(words) > 0 {
+			fmt.Fprintf(w, "\t\t%s) compadd -- %s ;;\n", command.Name, strings.Join(words, " "))
+		}
+	}
+	fmt.Fprintln(w, "\tesac")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, `if [ "$funcstack[1]" = "_spire" ]; then`)
+	fmt.Fprintln(w, `	_spire "$@"`)
+	fmt.Fprintln(w, "else")
+	fmt.Fprintln(w, "\tcompdef _spire spire")
+	fmt.Fprintln(w, "fi")
+}
+
+func writeFishCompletion(w io.Writer, registry []Command) {
+	fmt.Fprintln(w, "# fish completion for spire")
+	fmt.Fprintln(w, "complete -c spire -f")
+	for _, command := range registry {
+		fmt.Fprintf(w, "complete -c spire -n __fish_use_subcommand -a %s -d %s\n", command.Name, fishQuote(command.Summary))
+	}
+	for _, command := range registry {
+		condition := fishQuote("__fish_seen_subcommand_from " + command.Name)
+		if words := command.Words(); len(words) > 0 {
+			fmt.Fprintf(w, "complete -c spire -n %s -a %s\n", condition, fishQuote(strings.Join(words, " ")))
+		}
+		command.FlagSet().VisitAll(func(f *flag.Flag) {
+			_, usage := flag.UnquoteUsage(f)
+			required := " -r"
+			if isBoolFlag(f) {
+				required = ""
+			}
+			fmt.Fprintf(w, "complete -c spire -n %s -l %s%s -d %s\n", condition, f.Name, required, fishQuote(usage))
+		})
+	}
+}
+
+func zshQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func fishQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+}
@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRunCompletionListsCommandsAndFlags(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		exitCode := RunCompletion(context.Background(), []string{shell}, &stdout, &stderr)
+
+		if exitCode != 0 {
+			t.Fatalf("%s: exit code %d, stderr=%q", shell, exitCode, stderr.String())
+		}
+		for _, want := range []string{"init", "deinit", "completion", "dry-run", "prune"} {
+			if !strings.Contains(stdout.String(), want) {
+				t.Fatalf("%s completion missing %q:\n%s", shell, want, stdout.String())
+			}
+		}
+	}
+}
+
+func TestRunCompletionRejectsUnknownShell(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunCompletion(context.Background(), []string{"tcsh"}, &stdout, &stderr)
+
+	if exitCode != 1 {
+		t.Fatalf("exit code: got %d, want 1", exitCode)
+	}
+	if !strings.Contains(stderr.String(), `unsupported shell "tcsh"`) {
+		t.Fatalf("stderr: %q", stderr.String())
+	}
+}
@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"opencode-spire/internal/methodology"
+	"opencode-spire/internal/scaffold"
+)
+
+func declareDeinitFlags(flags *flag.FlagSet) (*bool, *bool) {
+	force := flags.Bool("force", false, "also remove root files modified since spire wrote them")
+	dryRun := flags.Bool("dry-run", false, "print what would be removed without changing anything")
+	return force, dryRun
+}
+
+func RunDeinit(ctx context.Context, args []string, projectRoot string, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("deinit", stderr)
+	force, dryRun := declareDeinitFlags(flags)
+	if done, code := parseFlags(flags, args, stdout); done {
+		return code
+	}
+	if flags.NArg() > 0 {
+		printUsage(stderr, "deinit")
+		return 1
+	}
+
+	methodologyPath := filepath.Join(projectRoot, ".methodology")
+	info, err := os.Stat(methodologyPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Fprintln(stderr, "Not initialized: .methodology not found")
+			return 1
+		}
+		fmt.Fprintf(stderr, "failed to inspect .methodology: %v\n", err)
+		return 1
+	}
+	if !info.IsDir() {
+		fmt.Fprintln(stderr, ".methodology exists but is not a directory")
+		return 1
+	}
+
+	steps, err := scaffold.PlanDeinit(projectRoot, methodologyPath, *force)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to plan project root removal: %v\n", err)
+		return 1
+	}
+
+	hasEntry, err := scaffold.HasGitignoreEntry(projectRoot, ".methodology/")
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to inspect .gitignore: %v\n", err)
+		return 1
+	}
+
+	settingsPath := filepath.Join(projectRoot, methodology.ProjectSettingsFilename)
+	hasSettings, err := pathExists(settingsPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to inspect %s: %v\n", methodology.ProjectSettingsFilename, err)
+		return 1
+	}
+
+	prefix := ""
+	if *dryRun {
+		prefix = "would "
+	}
+
+	for _, step := range steps {
+		switch step.Action {
+		case scaffold.DeinitRemove:
+			fmt.Fprintf(stdout, "%sremove: %s%s\n", prefix, step.Destination, formatReason(step.Reason))
+		case scaffold.DeinitStripBlock:
+			fmt.Fprintf(stdout, "%sremove block: %s%s\n", prefix, step.Destination, formatReason(step.Reason))
+		case scaffold.DeinitKeep:
+			fmt.Fprintf(stdout, "kept: %s%s\n", step.Destination, formatReason(step.Reason))
+		}
+	}
+	if hasEntry {
+		fmt.Fprintf(stdout, "%supdate: .gitignore (drop .methodology/)\n", prefix)
+	}
+	if hasSettings {
+		fmt.Fprintf(stdout, "%sremove: %s\n", prefix, methodology.ProjectSettingsFilename)
+	}
+	fmt.Fprintf(stdout, "%sremove: .methodology/\n", prefix)
+
+	if *dryRun {
+		fmt.Fprintln(stdout, "dry run: no files changed")
+		return 0
+	}
+
+	if err := scaffold.ApplyDeinit(ctx, projectRoot, steps); err != nil {
+		fmt.Fprintf(stderr, "failed to remove project root files: %v\n", err)
+		return 1
+	}
+
+	if _, err := scaffold.RemoveGitignoreEntry(projectRoot, ".methodology/"); err != nil {
+		fmt.Fprintf(stderr, "failed to update .gitignore: %v\n", err)
+		return 1
+	}
+
+	if hasSettings {
+		if err := os.Remove(settingsPath); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(stderr, "failed to remove %s: %v\n", methodology.ProjectSettingsFilename, err)
+			return 1
+		}
+	}
+
+	if err := os.RemoveAll(methodologyPath); err != nil {
+		fmt.Fprintf(stderr, "failed to remove .methodology: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintln(stdout, "removed spire scaffolding")
+	return 0
+}
+
+func formatReason(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	return " (" + reason + ")"
+}
@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func initProjectForDeinit(t *testing.T, source string) string {
+	t.Helper()
+
+	configureCanonicalSourceFromDir(t, source)
+	projectRoot := t.TempDir()
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+
+	writeFile(t, filepath.Join(projectRoot, "specs", "PRODUCT.md"), "# Product\n")
+	writeFile(t, filepath.Join(projectRoot, "changes", "001-demo", "SESSION.md"), "# Session\n")
+	return projectRoot
+}
+
+func TestRunDeinitRemovesUnmodifiedScaffolding(t *testing.T) {
+	projectRoot := initProjectForDeinit(t, createMethodologySource(t))
+	writeFile(t, filepath.Join(projectRoot, ".opencode", "agents", "verifier.md"), "---\nmode: subagent\n---\nmy own verifier\n")
+	writeFile(t, filepath.Join(projectRoot, ".spire.json"), "{\n  \"profile\": \"backend\"\n}\n")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunDeinit(context.Background(), nil, projectRoot, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+
+	for _, path := range []string{".methodology", ".spire.json", "AGENTS.md", "opencode.json", ".gitignore", filepath.Join(".opencode", "agents", "featureplanner.md")} {
+		if _, err := os.Stat(filepath.Join(projectRoot, path)); !os.IsNotExist(err) {
+			t.Fatalf("%s should be removed, stat err=%v", path, err)
+		}
+	}
+
+	assertFileContains(t, filepath.Join(projectRoot, ".opencode", "agents", "verifier.md"), "my own verifier")
+	if !strings.Contains(stdout.String(), "kept: .opencode/agents/verifier.md (modified locally; use --force to remove)") {
+		t.Fatalf("stdout: %q", stdout.String())
+	}
+	assertFileExists(t, filepath.Join(projectRoot, "specs", "PRODUCT.md"))
+	assertFileExists(t, filepath.Join(projectRoot, "changes", "001-demo", "SESSION.md"))
+}
+
+func TestRunDeinitForceRemovesModifiedFiles(t *testing.T) {
+	projectRoot := initProjectForDeinit(t, createMethodologySource(t))
+	writeFile(t, filepath.Join(projectRoot, ".opencode", "agents", "verifier.md"), "---\nmode: subagent\n---\nmy own verifier\n")
+	writeFile(t, filepath.Join(projectRoot, ".opencode", "agents", "mine.md"), "---\nmode: subagent\n---\nunrelated\n")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunDeinit(context.Background(), []string{"--force"}, projectRoot, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, ".opencode", "agents", "verifier.md")); !os.IsNotExist(err) {
+		t.Fatalf("verifier.md should be removed with --force, stat err=%v", err)
+	}
+	assertFileExists(t, filepath.Join(projectRoot, ".opencode", "agents", "mine.md"))
+}
+
+func TestRunDeinitJSONMergeUsesCreatedOrMergedState(t *testing.T) {
+	source := createMethodologySource(t)
+	setManifestPolicy(t, source, "opencode.json", "json_merge", "json_merge")
+
+	created := initProjectForDeinit(t, source)
+	writeFile(t, filepath.Join(created, "opencode.json"), "{\n  \"instructions\": [\"AGENTS.md\", \"docs/STYLE.md\"]\n}\n")
+
+	var stdout bytes.Buffer
+	if code := RunDeinit(context.Background(), []string{"--dry-run"}, created, &stdout, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("dry run exit code: got %d", code)
+	}
+	if !strings.Contains(stdout.String(), "kept: opencode.json (modified locally; use --force to remove)") {
+		t.Fatalf("stdout: %q", stdout.String())
+	}
+	if code := RunDeinit(context.Background(), []string{"--force"}, created, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("force exit code: got %d", code)
+	}
+	if _, err := os.Stat(filepath.Join(created, "opencode.json")); !os.IsNotExist(err) {
+		t.Fatalf("opencode.json created by spire should be removed with --force, stat err=%v", err)
+	}
+
+	merged := t.TempDir()
+	writeFile(t, filepath.Join(merged, "opencode.json"), "{\n  \"model\": \"mine\"\n}\n")
+	if code := RunInit(context.Background(), nil, merged, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+	stdout.Reset()
+	if code := RunDeinit(context.Background(), []string{"--force"}, merged, &stdout, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("force exit code: got %d", code)
+	}
+	if !strings.Contains(stdout.String(), "kept: opencode.json (existed before spire; merged keys left in place)") {
+		t.Fatalf("stdout: %q", stdout.String())
+	}
+	assertFileContains(t, filepath.Join(merged, "opencode.json"), "\"model\": \"mine\"")
+}
+
+func TestRunDeinitStripsManagedBlockAndKeepsGitignoreRules(t *testing.T) {
+	source := createMethodologySource(t)
+	setManifestPolicy(t, source, "AGENTS.md", "managed_block", "managed_block")
+	configureCanonicalSourceFromDir(t, source)
+	projectRoot := t.TempDir()
+	writeFile(t, filepath.Join(projectRoot, "AGENTS.md"), "# Team notes\n")
+	writeFile(t, filepath.Join(projectRoot, ".gitignore"), "node_modules/\n")
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunDeinit(context.Background(), nil, projectRoot, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if got := string(mustReadFile(t, filepath.Join(projectRoot, "AGENTS.md"))); got != "# Team notes\n" {
+		t.Fatalf("AGENTS.md: got %q", got)
+	}
+	if got := string(mustReadFile(t, filepath.Join(projectRoot, ".gitignore"))); got != "node_modules/\n" {
+		t.Fatalf(".gitignore: got %q", got)
+	}
+}
+
+func TestRunDeinitDryRunChangesNothing(t *testing.T) {
+	projectRoot := initProjectForDeinit(t, createMethodologySource(t))
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunDeinit(context.Background(), []string{"--dry-run"}, projectRoot, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	for _, want := range []string{"would remove: AGENTS.md", "would update: .gitignore", "would remove: .methodology/", "dry run: no files changed"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("stdout missing %q: %q", want, stdout.String())
+		}
+	}
+	assertFileExists(t, filepath.Join(projectRoot, ".methodology"))
+	assertFileExists(t, filepath.Join(projectRoot, "AGENTS.md"))
+	assertFileContains(t, filepath.Join(projectRoot, ".gitignore"), ".methodology/")
+}
+
+func TestRunDeinitRequiresInit(t *testing.T) {
+	var stderr bytes.Buffer
+	exitCode := RunDeinit(context.Background(), nil, t.TempDir(), &bytes.Buffer{}, &stderr)
+
+	if exitCode != 1 {
+		t.Fatalf("exit code: got %d, want 1", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "Not initialized") {
+		t.Fatalf("stderr: %q", stderr.String())
+	}
+}
@@ -0,0 +1,366 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"opencode-spire/internal/git"
+)
+
+// openRepo is replaced in tests with a git.Fake.
+var openRepo = func(ctx context.Context, dir string) (git.Repo, error) {
+	repo, err := git.Open(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// resolveFeature finds a feature by slug ("003-retry"), number ("3" or "003")
+// or name ("retry").
+func resolveFeature(projectRoot string, ref string) (featureEntry, error) {
+	ref = strings.TrimSpace(ref)
+	features, err := listFeatures(projectRoot)
+	if err != nil {
+		return featureEntry{}, fmt.Errorf("list features: %w", err)
+	}
+
+	number, numeric := strconv.Atoi(ref)
+	var matches []featureEntry
+	for _, feature := range features {
+		if feature.Slug == ref {
+			return feature, nil
+		}
+		featureNumber, _ := strconv.Atoi(feature.Number)
+		if feature.Name == ref || (numeric == nil && featureNumber == number) {
+			matches = append(matches, feature)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return featureEntry{}, fmt.Errorf("unknown feature %q", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		slugs := make([]string, 0, len(matches))
+		for _, match := range matches {
+			slugs = append(slugs, match.Slug)
+		}
+		return featureEntry{}, fmt.Errorf("feature %q is ambiguous (%s)", ref, strings.Join(slugs, ", "))
+	}
+}
+
+type featureMove struct {
+	From string
+	To   string
+}
+
+type featureRetitle struct {
+	File  string
+	Old   string
+	Title string
+}
+
+func declareFeatureFlags(flags *flag.FlagSet) (*bool, *bool) {
+	force := flags.Bool("force", false, "remove without asking for confirmation")
+	dryRun := flags.Bool("dry-run", false, "print what would change without changing anything")
+	return force, dryRun
+}
+
+func RunFeature(ctx context.Context, args []string, projectRoot string, stdin io.Reader, interactive bool, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("feature", stderr)
+	force, dryRun := declareFeatureFlags(flags)
+	if done, code := parseFlags(flags, args, stdout); done {
+		return code
+	}
+
+	positional := flags.Args()
+	switch {
+	case len(positional) == 3 && positional[0] == "rename":
+		return runFeatureRename(projectRoot, positional[1], positional[2], *dryRun, stdout, stderr)
+	case len(positional) == 3 && positional[0] == "renumber":
+		return runFeatureRenumber(projectRoot, positional[1], positional[2], *dryRun, stdout, stderr)
+	case len(positional) == 2 && positional[0] == "rm":
+		return runFeatureRemove(projectRoot, positional[1], *force, *dryRun, stdin, interactive, stdout, stderr)
+	default:
+		printUsage(stderr, "feature")
+		return 1
+	}
+}
+
+func runFeatureRename(projectRoot string, ref string, rawName string, dryRun bool, stdout io.Writer, stderr io.Writer) int {
+	feature, err := resolveFeature(projectRoot, ref)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+	name := normalizeFeatureName(rawName)
+	if name == "" {
+		fmt.Fprintln(stderr, "Name required.")
+		return 1
+	}
+	if name == feature.Name {
+		fmt.Fprintf(stderr, "%s is already named %s\n", feature.Slug, name)
+		return 1
+	}
+	if duplicate, err := featureNameExists(projectRoot, name); err != nil {
+		fmt.Fprintf(stderr, "failed to validate feature name uniqueness: %v\n", err)
+		return 1
+	} else if duplicate {
+		fmt.Fprintf(stderr, "Spec already exists for feature name: %s\n", name)
+		return 1
+	}
+
+	target := featureEntry{Number: feature.Number, Name: name, Slug: feature.Number + "-" + name}
+	return moveFeature(projectRoot, feature, target, dryRun, stdout, stderr)
+}
+
+func runFeatureRenumber(projectRoot string, ref string, rawNumber string, dryRun bool, stdout io.Writer, stderr io.Writer) int {
+	feature, err := resolveFeature(projectRoot, ref)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+	number, err := strconv.Atoi(strings.TrimSpace(rawNumber))
+	if err != nil || number < 1 {
+		fmt.Fprintf(stderr, "invalid feature number %q\n", rawNumber)
+		return 1
+	}
+	formatted := fmt.Sprintf("%03d", number)
+	if formatted == feature.Number {
+		fmt.Fprintf(stderr, "%s is already number %s\n", feature.Slug, formatted)
+		return 1
+	}
+
+	features, err := listFeatures(projectRoot)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to list features: %v\n", err)
+		return 1
+	}
+	for _, other := range features {
+		if otherNumber, _ := strconv.Atoi(other.Number); otherNumber == number && other.Slug != feature.Slug {
+			fmt.Fprintf(stderr, "refusing to renumber: %s already uses number %s\n", other.Slug, formatted)
+			return 1
+		}
+	}
+
+	target := featureEntry{Number: formatted, Name: feature.Name, Slug: formatted + "-" + feature.Name}
+	return moveFeature(projectRoot, feature, target, dryRun, stdout, stderr)
+}
+
+// runFeatureRemove deletes a feature's artifacts. Without --force it asks
+// first, and refuses outright when there is no terminal to ask on.
+func runFeatureRemove(projectRoot string, ref string, force bool, dryRun bool, stdin io.Reader, interactive bool, stdout io.Writer, stderr io.Writer) int {
+	feature, err := resolveFeature(projectRoot, ref)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+
+	paths, err := existingFeatureArtifacts(projectRoot, feature.Slug)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to inspect feature artifacts: %v\n", err)
+		return 1
+	}
+
+	prefix := ""
+	if dryRun {
+		prefix = "would "
+	}
+	for _, path := range paths {
+		fmt.Fprintf(stdout, "%sremove: %s\n", prefix, path)
+	}
+	if dryRun {
+		fmt.Fprintln(stdout, "dry run: no files changed")
+		return 0
+	}
+
+	if !force {
+		if !interactive {
+			fmt.Fprintf(stderr, "non-interactive mode: pass --force to remove %s.\n", feature.Slug)
+			return 1
+		}
+		if !confirmProceed(stdin, stderr) {
+			fmt.Fprintf(stderr, "kept %s\n", feature.Slug)
+			return 1
+		}
+	}
+
+	for _, path := range paths {
+		if err := os.RemoveAll(filepath.Join(projectRoot, filepath.FromSlash(path))); err != nil {
+			fmt.Fprintf(stderr, "failed to remove %s: %v\n", path, err)
+			return 1
+		}
+	}
+	fmt.Fprintf(stdout, "removed %s\n", feature.Slug)
+	return 0
+}
+
+// moveFeature renames every artifact of feature to target's slug and retitles
+// the spec and session log when they still carry the old name. Nothing moves
+// if any destination already exists, and a failed move or retitle undoes what
+// was already done.
+func moveFeature(projectRoot string, feature featureEntry, target featureEntry, dryRun bool, stdout io.Writer, stderr io.Writer) int {
+	sources, err := existingFeatureArtifacts(projectRoot, feature.Slug)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to inspect feature artifacts: %v\n", err)
+		return 1
+	}
+
+	targets := featureArtifacts(target.Slug)
+	var moves []featureMove
+	for i, source := range featureArtifacts(feature.Slug) {
+		if exists, err := pathExists(filepath.Join(projectRoot, filepath.FromSlash(targets[i]))); err != nil {
+			fmt.Fprintf(stderr, "failed to inspect %s: %v\n", targets[i], err)
+			return 1
+		} else if exists {
+			fmt.Fprintf(stderr, "refusing to move %s: %s already exists\n", feature.Slug, targets[i])
+			return 1
+		}
+		if containsString(sources, source) {
+			moves = append(moves, featureMove{From: source, To: targets[i]})
+		}
+	}
+
+	retitles, err := planFeatureRetitles(projectRoot, feature, target)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to read feature titles: %v\n", err)
+		return 1
+	}
+
+	prefix := ""
+	if dryRun {
+		prefix = "would "
+	}
+	for _, move := range moves {
+		fmt.Fprintf(stdout, "%smove: %s -> %s\n", prefix, move.From, move.To)
+	}
+	for _, retitle := range retitles {
+		fmt.Fprintf(stdout, "%sretitle: %s (%s)\n", prefix, retitle.File, retitle.Title)
+	}
+	if dryRun {
+		fmt.Fprintln(stdout, "dry run: no files changed")
+		return 0
+	}
+
+	for i, move := range moves {
+		if err := os.Rename(filepath.Join(projectRoot, filepath.FromSlash(move.From)), filepath.Join(projectRoot, filepath.FromSlash(move.To))); err != nil {
+			undoFeatureMoves(projectRoot, moves[:i])
+			fmt.Fprintf(stderr, "failed to move %s: %v\n", move.From, err)
+			return 1
+		}
+	}
+	for i, retitle := range retitles {
+		if err := replaceFirstLine(filepath.Join(projectRoot, filepath.FromSlash(retitle.File)), retitle.Old, retitle.Title); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				replaceFirstLine(filepath.Join(projectRoot, filepath.FromSlash(retitles[j].File)), retitles[j].Title, retitles[j].Old)
+			}
+			undoFeatureMoves(projectRoot, moves)
+			fmt.Fprintf(stderr, "failed to retitle %s: %v\n", retitle.File, err)
+			return 1
+		}
+	}
+
+	fmt.Fprintf(stdout, "%s is now %s\n", feature.Slug, target.Slug)
+	return 0
+}
+
+// undoFeatureMoves moves already renamed artifacts back, newest first.
+func undoFeatureMoves(projectRoot string, moves []featureMove) {
+	for i := len(moves) - 1; i >= 0; i-- {
+		os.Rename(filepath.Join(projectRoot, filepath.FromSlash(moves[i].To)), filepath.Join(projectRoot, filepath.FromSlash(moves[i].From)))
+	}
+}
+
+// featureArtifacts lists the project-relative paths named after a slug, in a
+// fixed order so a feature's paths line up with its target's.
+func featureArtifacts(slug string) []string {
+	return []string{
+		"specs/feature-" + slug + ".md",
+		"specs/feature-" + slug + "-AUDIT.md",
+		"changes/" + slug,
+		"archive/" + slug,
+	}
+}
+
+func existingFeatureArtifacts(projectRoot string, slug string) ([]string, error) {
+	var paths []string
+	for _, path := range featureArtifacts(slug) {
+		exists, err := pathExists(filepath.Join(projectRoot, filepath.FromSlash(path)))
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// planFeatureRetitles finds the `# Spec:` and `# Session Log:` headings that
+// still carry the old name, at the paths they will have after the move.
+// Headings someone has rewritten by hand are left alone.
+func planFeatureRetitles(projectRoot string, feature featureEntry, target featureEntry) ([]featureRetitle, error) {
+	if feature.Name == target.Name {
+		return nil, nil
+	}
+	candidates := []struct {
+		from   string
+		to     string
+		prefix string
+	}{
+		{"specs/feature-" + feature.Slug + ".md", "specs/feature-" + target.Slug + ".md", "# Spec: "},
+		{"changes/" + feature.Slug + "/SESSION.md", "changes/" + target.Slug + "/SESSION.md", "# Session Log: "},
+		{"archive/" + feature.Slug + "/SESSION.md", "archive/" + target.Slug + "/SESSION.md", "# Session Log: "},
+	}
+
+	var retitles []featureRetitle
+	for _, candidate := range candidates {
+		data, err := os.ReadFile(filepath.Join(projectRoot, filepath.FromSlash(candidate.from)))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		old := candidate.prefix + feature.Name
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.TrimSpace(line) == old {
+				retitles = append(retitles, featureRetitle{File: candidate.to, Old: old, Title: candidate.prefix + target.Name})
+				break
+			}
+		}
+	}
+	return retitles, nil
+}
+
+func replaceFirstLine(path string, old string, replacement string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == old {
+			lines[i] = replacement
+			break
+		}
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644)
+}
+
+func containsString(values []string, want string) bool {
+	for _, value := range values {
+		if value == want {
+			return true
+		}
+	}
+	return false
+}
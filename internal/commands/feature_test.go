@@ -0,0 +1,155 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func createRenameFeature(t *testing.T) string {
+	t.Helper()
+
+	projectRoot := t.TempDir()
+	writeFile(t, filepath.Join(projectRoot, "specs", "feature-003-retry.md"), "# Spec: retry\n\nBody mentions retry.\n")
+	writeFile(t, filepath.Join(projectRoot, "specs", "feature-003-retry-AUDIT.md"), "# Audit\n")
+	writeFile(t, filepath.Join(projectRoot, "changes", "003-retry", "SESSION.md"), "# Session Log: retry\n")
+	writeFile(t, filepath.Join(projectRoot, "changes", "003-retry", "PLAN.md"), "# Plan\n")
+	writeFile(t, filepath.Join(projectRoot, "specs", "feature-004-cache.md"), "# Spec: cache\n")
+	return projectRoot
+}
+
+func TestRunFeatureRenameMovesArtifactsAndTitles(t *testing.T) {
+	projectRoot := createRenameFeature(t)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if code := RunFeature(context.Background(), []string{"rename", "003", "Backoff Retry"}, projectRoot, strings.NewReader(""), false, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "move: changes/003-retry -> changes/003-backoff-retry\n") {
+		t.Fatalf("stdout: %q", stdout.String())
+	}
+
+	assertFileContains(t, filepath.Join(projectRoot, "specs", "feature-003-backoff-retry.md"), "# Spec: backoff-retry\n\nBody mentions retry.\n")
+	assertFileExists(t, filepath.Join(projectRoot, "specs", "feature-003-backoff-retry-AUDIT.md"))
+	assertFileContains(t, filepath.Join(projectRoot, "changes", "003-backoff-retry", "SESSION.md"), "# Session Log: backoff-retry\n")
+	assertFileExists(t, filepath.Join(projectRoot, "changes", "003-backoff-retry", "PLAN.md"))
+	for _, path := range []string{"specs/feature-003-retry.md", "specs/feature-003-retry-AUDIT.md", "changes/003-retry"} {
+		if _, err := os.Stat(filepath.Join(projectRoot, path)); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be moved, stat err=%v", path, err)
+		}
+	}
+}
+
+func TestRunFeatureRefusesCollisions(t *testing.T) {
+	projectRoot := createRenameFeature(t)
+	writeFile(t, filepath.Join(projectRoot, "archive", "005-retry", "SESSION.md"), "# Session Log: stray\n")
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "name in use", args: []string{"rename", "003", "cache"}, want: "Spec already exists for feature name: cache"},
+		{name: "number in use", args: []string{"renumber", "003", "4"}, want: "004-cache already uses number 004"},
+		{name: "destination exists", args: []string{"renumber", "003", "5"}, want: "archive/005-retry already exists"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			var stderr bytes.Buffer
+			if code := RunFeature(context.Background(), tt.args, projectRoot, strings.NewReader(""), false, &stdout, &stderr); code != 1 {
+				t.Fatalf("exit code: got %d, want 1", code)
+			}
+			if !strings.Contains(stderr.String(), tt.want) {
+				t.Fatalf("stderr: %q", stderr.String())
+			}
+		})
+	}
+	assertFileExists(t, filepath.Join(projectRoot, "specs", "feature-003-retry.md"))
+}
+
+func TestRunFeatureDryRunChangesNothing(t *testing.T) {
+	projectRoot := createRenameFeature(t)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if code := RunFeature(context.Background(), []string{"--dry-run", "renumber", "retry", "12"}, projectRoot, strings.NewReader(""), false, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", code, stderr.String())
+	}
+	for _, want := range []string{
+		"would move: specs/feature-003-retry.md -> specs/feature-012-retry.md\n",
+		"would move: changes/003-retry -> changes/012-retry\n",
+		"dry run: no files changed\n",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("stdout missing %q: %q", want, stdout.String())
+		}
+	}
+	assertFileExists(t, filepath.Join(projectRoot, "changes", "003-retry", "PLAN.md"))
+
+	stdout.Reset()
+	if code := RunFeature(context.Background(), []string{"--dry-run", "rm", "003"}, projectRoot, strings.NewReader(""), false, &stdout, &stderr); code != 0 {
+		t.Fatalf("rm exit code: got %d, stderr=%q", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "would remove: changes/003-retry\n") {
+		t.Fatalf("stdout: %q", stdout.String())
+	}
+	assertFileExists(t, filepath.Join(projectRoot, "changes", "003-retry", "PLAN.md"))
+}
+
+func TestRunFeatureRemoveDeletesArtifacts(t *testing.T) {
+	projectRoot := createRenameFeature(t)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if code := RunFeature(context.Background(), []string{"rm", "003", "--force"}, projectRoot, strings.NewReader(""), false, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", code, stderr.String())
+	}
+	for _, path := range []string{"specs/feature-003-retry.md", "specs/feature-003-retry-AUDIT.md", "changes/003-retry"} {
+		if _, err := os.Stat(filepath.Join(projectRoot, path)); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be removed, stat err=%v", path, err)
+		}
+	}
+	assertFileExists(t, filepath.Join(projectRoot, "specs", "feature-004-cache.md"))
+}
+
+func TestRunFeatureRemoveAsksBeforeDeleting(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		interactive bool
+		want        string
+	}{
+		{name: "non-interactive", interactive: false, want: "pass --force to remove 003-retry"},
+		{name: "declined", input: "n\n", interactive: true, want: "kept 003-retry"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectRoot := createRenameFeature(t)
+
+			var stdout bytes.Buffer
+			var stderr bytes.Buffer
+			if code := RunFeature(context.Background(), []string{"rm", "003"}, projectRoot, strings.NewReader(tt.input), tt.interactive, &stdout, &stderr); code != 1 {
+				t.Fatalf("exit code: got %d, want 1", code)
+			}
+			if !strings.Contains(stderr.String(), tt.want) {
+				t.Fatalf("stderr: got %q, want %q", stderr.String(), tt.want)
+			}
+			assertFileExists(t, filepath.Join(projectRoot, "changes", "003-retry", "PLAN.md"))
+		})
+	}
+
+	projectRoot := createRenameFeature(t)
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if code := RunFeature(context.Background(), []string{"rm", "003"}, projectRoot, strings.NewReader("y\n"), true, &stdout, &stderr); code != 0 {
+		t.Fatalf("confirmed exit code: got %d, stderr=%q", code, stderr.String())
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, "changes", "003-retry")); !os.IsNotExist(err) {
+		t.Fatalf("expected changes/003-retry to be removed, stat err=%v", err)
+	}
+}
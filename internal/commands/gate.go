@@ -0,0 +1,168 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"opencode-spire/internal/gate"
+)
+
+type gateFlags struct {
+	feature *string
+	gate    *int
+	base    *string
+	format  *string
+	junit   *string
+}
+
+func declareGateFlags(flags *flag.FlagSet) gateFlags {
+	return gateFlags{
+		feature: flags.String("feature", "", "check only this `feature` (slug, number, or name)"),
+		gate:    flags.Int("gate", 0, "check only gate `N` (1 audit, 2 approval, 4 verification)"),
+		base:    flags.String("base", "", "compare HEAD with `ref` to find the pull request's changed files (default origin/$GITHUB_BASE_REF in Actions)"),
+		format:  flags.String("format", "", "output `format`: text or github (default github in GitHub Actions)"),
+		junit:   flags.String("junit", "", "also write a JUnit XML report to `file`"),
+	}
+}
+
+func RunGate(ctx context.Context, args []string, projectRoot string, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("gate", stderr)
+	options := declareGateFlags(flags)
+	if done, code := parseFlags(flags, args, stdout); done {
+		return code
+	}
+	if flags.NArg() != 1 || flags.Arg(0) != "check" {
+		printUsage(stderr, "gate")
+		return 1
+	}
+
+	format := *options.format
+	if format == "" {
+		format = "text"
+		if os.Getenv("GITHUB_ACTIONS") == "true" {
+			format = "github"
+		}
+	}
+	if format != "text" && format != "github" {
+		fmt.Fprintf(stderr, "unsupported format %q (want text or github)\n", format)
+		return 1
+	}
+
+	input := gate.Input{ProjectRoot: projectRoot, Gates: gate.Gates}
+	if *options.gate != 0 {
+		if !containsInt(gate.Gates, *options.gate) {
+			fmt.Fprintf(stderr, "no checks for gate %d (want 1, 2, or 4)\n", *options.gate)
+			return 1
+		}
+		input.Gates = []int{*options.gate}
+	}
+
+	features, err := gateFeatures(projectRoot, *options.feature)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+	input.Features = features
+	input.Selected = *options.feature != ""
+
+	base := *options.base
+	if base == "" && os.Getenv("GITHUB_BASE_REF") != "" {
+		base = "origin/" + os.Getenv("GITHUB_BASE_REF")
+	}
+	if base != "" {
+		repo, err := openRepo(ctx, projectRoot)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to open git repository: %v\n", err)
+			return 1
+		}
+		if input.Changed, err = repo.DiffFiles(ctx, base, "HEAD"); err != nil {
+			fmt.Fprintf(stderr, "failed to list changed files: %v\n", err)
+			return 1
+		}
+		if input.Changed == nil {
+			input.Changed = []string{}
+		}
+	}
+
+	results, err := gate.Evaluate(input)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to check gates: %v\n", err)
+		return 1
+	}
+
+	if format == "github" {
+		gate.WriteAnnotations(stdout, results)
+	} else {
+		gate.WriteText(stdout, results)
+	}
+
+	if *options.junit != "" {
+		if err := writeJUnitReport(projectRoot, *options.junit, results); err != nil {
+			fmt.Fprintf(stderr, "failed to write JUnit report: %v\n", err)
+			return 1
+		}
+	}
+
+	failures := gate.Failures(results)
+	fmt.Fprintf(stdout, "gate check: %d checked, %d failed\n", len(results), failures)
+	if failures > 0 {
+		return 1
+	}
+	return 0
+}
+
+// gateFeatures returns the named feature, or every feature that has not been
+// archived.
+func gateFeatures(projectRoot string, ref string) ([]string, error) {
+	if ref != "" {
+		feature, err := resolveFeature(projectRoot, ref)
+		if err != nil {
+			return nil, err
+		}
+		return []string{feature.Slug}, nil
+	}
+
+	features, err := listFeatures(projectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list features: %w", err)
+	}
+	var slugs []string
+	for _, feature := range features {
+		archived, err := pathExists(filepath.Join(projectRoot, "archive", feature.Slug))
+		if err != nil {
+			return nil, err
+		}
+		if !archived {
+			slugs = append(slugs, feature.Slug)
+		}
+	}
+	return slugs, nil
+}
+
+func writeJUnitReport(projectRoot string, path string, results []gate.Result) error {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectRoot, path)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %q: %w", path, err)
+	}
+	if err := gate.WriteJUnit(file, results); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func containsInt(values []int, want int) bool {
+	for _, value := range values {
+		if value == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"opencode-spire/internal/approval"
+	"opencode-spire/internal/git"
+)
+
+func TestRunGateCheckAnnotatesPullRequestViolations(t *testing.T) {
+	projectRoot := t.TempDir()
+	writeFile(t, filepath.Join(projectRoot, "specs", "feature-003-retry.md"), "# Spec\n")
+	writeFile(t, filepath.Join(projectRoot, "specs", "feature-003-retry-AUDIT.md"), "VERDICT: PASS (42/50)\n")
+	writeFile(t, filepath.Join(projectRoot, "changes", "003-retry", "PLAN.md"), "# Plan\n")
+	writeFile(t, filepath.Join(projectRoot, "changes", "003-retry", "TASKS.md"), "- [ ] T1: retry\n")
+	if _, err := approval.Approve(projectRoot, "003-retry", []string{"PLAN.md"}, "Ada", time.Now()); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+
+	original := openRepo
+	t.Cleanup(func() { openRepo = original })
+	openRepo = func(ctx context.Context, dir string) (git.Repo, error) {
+		return &git.Fake{
+			Head: "b1",
+			Refs: map[string]string{"main": "m1"},
+			Commits: map[string]git.FakeCommit{
+				"m1": {},
+				"b1": {Parents: []string{"m1"}, Files: []string{"internal/retry.go", "changes/003-retry/TASKS.md"}},
+			},
+		}, nil
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunGate(context.Background(), []string{"check", "--base", "main", "--format", "github", "--junit", "gates.xml"}, projectRoot, &stdout, &stderr)
+
+	if exitCode != 1 {
+		t.Fatalf("exit code: got %d, want 1 (stderr=%q)", exitCode, stderr.String())
+	}
+	want := "::error file=changes/003-retry/TASKS.md,title=Gate 2 (003-retry)::TASKS.md is not approved; implementation must wait for spire approve\n" +
+		"::error file=changes/003-retry/PLAN.md,title=Gate 4 (003-retry)::VERIFICATION_REPORT.md is missing; run verification before merge\n" +
+		"gate check: 3 checked, 2 failed\n"
+	if stdout.String() != want {
+		t.Fatalf("stdout:\n%s\nwant:\n%s", stdout.String(), want)
+	}
+	assertFileContains(t, filepath.Join(projectRoot, "gates.xml"), `failures="2"`)
+}
+
+func TestRunGateCheckPassesWithoutPlanning(t *testing.T) {
+	projectRoot := t.TempDir()
+	writeFile(t, filepath.Join(projectRoot, "specs", "feature-001-alpha.md"), "# Spec\n")
+	t.Setenv("GITHUB_ACTIONS", "")
+	t.Setenv("GITHUB_BASE_REF", "")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if code := RunGate(context.Background(), []string{"check", "--gate", "1"}, projectRoot, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "skip gate 1 001-alpha: no PLAN.md or TASKS.md yet") {
+		t.Fatalf("stdout: %q", stdout.String())
+	}
+
+	if code := RunGate(context.Background(), []string{"check", "--gate", "3"}, projectRoot, &stdout, &stderr); code != 1 {
+		t.Fatalf("--gate 3 exit code: got %d, want 1", code)
+	}
+}
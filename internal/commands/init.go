@@ -1,40 +1,280 @@
 package commands
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"opencode-spire/internal/download"
 	"opencode-spire/internal/methodology"
 	"opencode-spire/internal/scaffold"
 )
 
-func RunInit(args []string, projectRoot string, stdout io.Writer, stderr io.Writer) int {
-	methodologyPath := filepath.Join(projectRoot, ".methodology")
-	if _, err := os.Stat(methodologyPath); err == nil {
-		fmt.Fprintln(stderr, "Already initialized: .methodology exists")
+type initFlags struct {
+	profile *string
+	target  *string
+	reinit  *bool
+	repair  *bool
+	offline *bool
+	source  *string
+	timeout *time.Duration
+}
+
+func declareInitFlags(flags *flag.FlagSet) initFlags {
+	return initFlags{
+		profile: flags.String("profile", "", "project profile `name` used to select manifest mappings"),
+		target:  flags.String("target", "", "comma-separated agent `runtimes` to project (default opencode)"),
+		reinit:  flags.Bool("reinit", false, "re-fetch the payload into an existing .methodology and project missing root files"),
+		repair:  flags.Bool("repair", false, "restore files missing from .methodology without overwriting local edits"),
+		offline: flags.Bool("offline", false, "use the newest cached methodology payload instead of downloading"),
+		source:  flags.String("source", "online", "methodology payload `source`: online or embedded"),
+		timeout: flags.Duration("timeout", download.DefaultTimeout, "timeout for each download attempt"),
+	}
+}
+
+func RunInit(ctx context.Context, args []string, projectRoot string, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("init", stderr)
+	options := declareInitFlags(flags)
+	if done, code := parseFlags(flags, args, stdout); done {
+		return code
+	}
+	if flags.NArg() > 0 {
+		printUsage(stderr, "init")
+		return 1
+	}
+	if *options.source != "online" && *options.source != "embedded" {
+		fmt.Fprintf(stderr, "unknown source %q (want online or embedded)\n", *options.source)
+		return 1
+	}
+	if *options.reinit && *options.repair {
+		fmt.Fprintln(stderr, "--reinit and --repair cannot be combined")
 		return 1
+	}
+
+	methodologyPath := filepath.Join(projectRoot, ".methodology")
+	exists := false
+	if info, err := os.Stat(methodologyPath); err == nil {
+		if !info.IsDir() {
+			fmt.Fprintln(stderr, ".methodology exists but is not a directory")
+			return 1
+		}
+		exists = true
 	} else if !os.IsNotExist(err) {
 		fmt.Fprintf(stderr, "failed to inspect .methodology: %v\n", err)
 		return 1
 	}
 
-	if _, _, err := methodology.SyncCanonicalToProject(projectRoot); err != nil {
-		fmt.Fprintf(stderr, "failed to initialize methodology payload: %v\n", err)
+	opts := fetchOptions(*options.offline, *options.timeout, stderr)
+
+	if *options.repair {
+		if !exists {
+			fmt.Fprintln(stderr, "Not initialized: .methodology not found")
+			return 1
+		}
+		return repairMethodology(ctx, methodologyPath, opts, stdout, stderr)
+	}
+
+	if exists && !*options.reinit {
+		fmt.Fprintln(stderr, "Already initialized: .methodology exists (use --reinit or --repair)")
 		return 1
 	}
 
+	if exists {
+		discarded, err := methodology.DiscardUnreadableState(methodologyPath)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to reset unreadable methodology state: %v\n", err)
+			return 1
+		}
+		for _, cause := range discarded {
+			fmt.Fprintf(stderr, "warning: discarded unreadable %v\n", cause)
+		}
+	}
+
+	source := methodology.DefaultSourceMetadata()
+	if exists {
+		source = recordedSource(methodologyPath, stderr).OnlineSource()
+	}
+	if *options.source == "embedded" {
+		source = methodology.EmbeddedSourceMetadata()
+	}
+
+	hadGitignoreEntry, err := scaffold.HasGitignoreEntry(projectRoot, ".methodology/")
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to inspect .gitignore: %v\n", err)
+		return 1
+	}
+	fail := func() int {
+		if !exists && ctx.Err() != nil {
+			rollbackInit(projectRoot, hadGitignoreEntry, stderr)
+		}
+		return 1
+	}
+
+	err = syncPayload(ctx, projectRoot, exists, source, opts)
+	if err != nil && !source.Embedded && ctx.Err() == nil && fetchFailed(err) {
+		fmt.Fprintf(stderr, "warning: failed to fetch methodology: %v\n", err)
+		fmt.Fprintf(stderr, "warning: falling back to embedded methodology payload (version %s)\n", methodology.EmbeddedPayloadVersion())
+		source = methodology.EmbeddedSourceMetadata()
+		err = syncPayload(ctx, projectRoot, exists, source, methodology.FetchOptions{})
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to initialize methodology payload: %v\n", err)
+		return fail()
+	}
+	if source.Embedded {
+		fmt.Fprintf(stdout, "source: embedded payload (version %s)\n", source.Version)
+	}
+
+	// Settings are committed, so a fresh clone picks up the team's profile.
+	settings, err := methodology.ReadProjectSettings(projectRoot)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to read %s: %v\n", methodology.ProjectSettingsFilename, err)
+		return fail()
+	}
+
+	if name := strings.TrimSpace(*options.profile); name != "" {
+		settings.Profile = name
+	}
+	if targets := splitTargets(*options.target); len(targets) > 0 {
+		if err := checkTargets(methodologyPath, targets); err != nil {
+			fmt.Fprintf(stderr, "failed to select targets: %v\n", err)
+			return fail()
+		}
+		settings.Targets = targets
+	}
+
+	if settings.Profile != "" || len(settings.Targets) > 0 {
+		if err := methodology.WriteProjectSettings(projectRoot, settings); err != nil {
+			fmt.Fprintf(stderr, "failed to record project settings: %v\n", err)
+			return fail()
+		}
+	}
+	if settings.Profile != "" {
+		fmt.Fprintf(stdout, "profile: %s\n", settings.Profile)
+	}
+	if len(settings.Targets) > 0 {
+		fmt.Fprintf(stdout, "targets: %s\n", strings.Join(settings.Targets, ", "))
+	}
+
 	if err := scaffold.EnsureGitignoreEntry(projectRoot, ".methodology/"); err != nil {
 		fmt.Fprintf(stderr, "failed to update .gitignore: %v\n", err)
-		return 1
+		return fail()
 	}
 
-	if err := scaffold.ApplyProjectRootInitMappings(projectRoot, methodologyPath, stdout); err != nil {
+	apply := scaffold.ApplyProjectRootInitMappings
+	if exists {
+		apply = scaffold.ApplyProjectRootMissingMappings
+	}
+	if err := apply(ctx, projectRoot, methodologyPath, stdout); err != nil {
 		fmt.Fprintf(stderr, "failed to apply project root mappings: %v\n", err)
+		return fail()
+	}
+
+	warnOpencodeIssues(projectRoot, stderr)
+
+	if exists {
+		fmt.Fprintln(stdout, "reinitialized .methodology")
+	} else {
+		fmt.Fprintln(stdout, "initialized .methodology")
+	}
+	return 0
+}
+
+// rollbackInit removes what an interrupted first init left behind; root files
+// are already restored by the projector.
+func rollbackInit(projectRoot string, hadGitignoreEntry bool, stderr io.Writer) {
+	if err := os.RemoveAll(filepath.Join(projectRoot, ".methodology")); err != nil {
+		fmt.Fprintf(stderr, "failed to remove .methodology: %v\n", err)
+	}
+	if !hadGitignoreEntry {
+		if _, err := scaffold.RemoveGitignoreEntry(projectRoot, ".methodology/"); err != nil {
+			fmt.Fprintf(stderr, "failed to update .gitignore: %v\n", err)
+		}
+	}
+	fmt.Fprintln(stderr, "rolled back partial init")
+}
+
+// fetchFailed reports whether err came from reaching the methodology source,
+// the only kind of failure the embedded payload can stand in for.
+func fetchFailed(err error) bool {
+	var downloadErr *download.Error
+	return errors.As(err, &downloadErr) || errors.Is(err, methodology.ErrNoCachedPayload)
+}
+
+func syncPayload(ctx context.Context, projectRoot string, existing bool, source methodology.SourceMetadata, opts methodology.FetchOptions) error {
+	if existing {
+		_, _, err := methodology.SyncAndReportChangesFromMetadata(ctx, filepath.Join(projectRoot, ".methodology"), source, opts)
+		return err
+	}
+
+	_, _, err := methodology.SyncSourceToProject(ctx, source, projectRoot, opts)
+	return err
+}
+
+func repairMethodology(ctx context.Context, methodologyPath string, opts methodology.FetchOptions, stdout io.Writer, stderr io.Writer) int {
+	report, err := methodology.RepairFromMetadata(ctx, methodologyPath, recordedSource(methodologyPath, stderr), opts)
+	if errors.Is(err, methodology.ErrNoSyncState) {
+		fmt.Fprintln(stderr, "cannot repair: no sync state in .methodology; use spire init --reinit")
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to repair methodology payload: %v\n", err)
 		return 1
 	}
 
-	fmt.Fprintln(stdout, "initialized .methodology")
+	for _, path := range report.Restored {
+		fmt.Fprintf(stdout, "restored: %s\n", path)
+	}
+	for _, path := range report.Unavailable {
+		fmt.Fprintf(stderr, "cannot restore %s: its recorded version is neither cached nor upstream\n", path)
+	}
+
+	switch {
+	case len(report.Unavailable) > 0:
+		fmt.Fprintln(stderr, "repair incomplete: use spire init --reinit to move to the current payload")
+		return 1
+	case len(report.Restored) == 0:
+		fmt.Fprintln(stdout, "nothing to repair")
+	default:
+		fmt.Fprintln(stdout, "repaired .methodology")
+	}
 	return 0
 }
+
+func recordedSource(methodologyPath string, stderr io.Writer) methodology.SourceMetadata {
+	source := methodology.DefaultSourceMetadata()
+	if metadata, err := methodology.ReadSourceMetadata(methodologyPath); err != nil {
+		fmt.Fprintf(stderr, "warning: using canonical source: %v\n", err)
+	} else if metadata != nil {
+		source = *metadata
+	}
+	return source
+}
+
+func splitTargets(value string) []string {
+	var targets []string
+	seen := map[string]bool{}
+	for _, part := range strings.Split(value, ",") {
+		name := strings.TrimSpace(part)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		targets = append(targets, name)
+	}
+	return targets
+}
+
+func checkTargets(methodologyPath string, targets []string) error {
+	manifest, err := scaffold.LoadProjectRootManifest(filepath.Join(methodologyPath, "project_root", "manifest.json"))
+	if err != nil {
+		return err
+	}
+	return scaffold.CheckTargets(manifest, targets)
+}
@@ -4,6 +4,7 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -11,7 +12,10 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
+	"opencode-spire/internal/download"
 	"opencode-spire/internal/methodology"
 )
 
@@ -22,7 +26,7 @@ func TestRunInitHappyPathCreatesMethodologyAndRootProjection(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunInit(nil, projectRoot, &stdout, &stderr)
+	exitCode := RunInit(context.Background(), nil, projectRoot, &stdout, &stderr)
 
 	if exitCode != 0 {
 		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
@@ -49,7 +53,7 @@ func TestRunInitAlreadyInitializedAborts(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunInit(nil, projectRoot, &stdout, &stderr)
+	exitCode := RunInit(context.Background(), nil, projectRoot, &stdout, &stderr)
 
 	if exitCode != 1 {
 		t.Fatalf("exit code: got %d, want 1", exitCode)
@@ -60,6 +64,175 @@ func TestRunInitAlreadyInitializedAborts(t *testing.T) {
 	}
 }
 
+func TestRunInitReinitRecoversEmptyMethodology(t *testing.T) {
+	source := createMethodologySource(t)
+	configureCanonicalSourceFromDir(t, source)
+	projectRoot := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(projectRoot, ".methodology"), 0o755); err != nil {
+		t.Fatalf("mkdir .methodology: %v", err)
+	}
+	writeFile(t, filepath.Join(projectRoot, "AGENTS.md"), "# Mine\n")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunInit(context.Background(), []string{"--reinit"}, projectRoot, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+
+	assertFileExists(t, filepath.Join(projectRoot, ".methodology", "agents", "SPIRE.md"))
+	assertFileExists(t, filepath.Join(projectRoot, ".methodology", ".spire-sync-state.json"))
+	assertFileExists(t, filepath.Join(projectRoot, "opencode.json"))
+	if got := string(mustReadFile(t, filepath.Join(projectRoot, "AGENTS.md"))); got != "# Mine\n" {
+		t.Fatalf("AGENTS.md should be untouched, got %q", got)
+	}
+	for _, want := range []string{"skipped existing: AGENTS.md", "reinitialized .methodology"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("stdout missing %q: %q", want, stdout.String())
+		}
+	}
+}
+
+func TestRunInitReinitDiscardsCorruptState(t *testing.T) {
+	source := createMethodologySource(t)
+	configureCanonicalSourceFromDir(t, source)
+	projectRoot := t.TempDir()
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+
+	methodologyPath := filepath.Join(projectRoot, ".methodology")
+	writeFile(t, filepath.Join(methodologyPath, ".spire-sync-state.json"), "{not json")
+	writeFile(t, filepath.Join(methodologyPath, ".spire-project.json"), "{not json")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunInit(context.Background(), []string{"--reinit"}, projectRoot, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	for _, want := range []string{"warning: discarded unreadable .spire-sync-state.json", "warning: discarded unreadable .spire-project.json"} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Fatalf("stderr missing %q: %q", want, stderr.String())
+		}
+	}
+	for _, unwanted := range []string{"failed to fetch", "ignoring unreadable project settings"} {
+		if strings.Contains(stderr.String(), unwanted) {
+			t.Fatalf("stderr should not contain %q: %q", unwanted, stderr.String())
+		}
+	}
+	if dirty, err := methodology.DetectDirty(methodologyPath); err != nil || len(dirty) != 0 {
+		t.Fatalf("detect dirty after reinit: %v, %v", dirty, err)
+	}
+}
+
+func TestRunInitRepairRestoresOnlyMissingFiles(t *testing.T) {
+	source := createMethodologySource(t)
+	configureCanonicalSourceFromDir(t, source)
+	projectRoot := t.TempDir()
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+
+	methodologyPath := filepath.Join(projectRoot, ".methodology")
+	if err := os.Remove(filepath.Join(methodologyPath, "agents", "SPIRE.md")); err != nil {
+		t.Fatalf("remove SPIRE.md: %v", err)
+	}
+	writeFile(t, filepath.Join(methodologyPath, "skills", "spec-auditor.md"), "# My spec rules\n")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunInit(context.Background(), []string{"--repair"}, projectRoot, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "restored: agents/SPIRE.md") {
+		t.Fatalf("stdout: %q", stdout.String())
+	}
+	assertFileContains(t, filepath.Join(methodologyPath, "agents", "SPIRE.md"), "# SPIRE")
+	if got := string(mustReadFile(t, filepath.Join(methodologyPath, "skills", "spec-auditor.md"))); got != "# My spec rules\n" {
+		t.Fatalf("edited file should be kept, got %q", got)
+	}
+
+	dirty, err := methodology.DetectDirty(methodologyPath)
+	if err != nil {
+		t.Fatalf("detect dirty: %v", err)
+	}
+	if len(dirty) != 1 || dirty[0] != "skills/spec-auditor.md" {
+		t.Fatalf("dirty files: %v", dirty)
+	}
+}
+
+func TestRunInitRepairUsesRecordedPayload(t *testing.T) {
+	source := createMethodologySource(t)
+	configureCanonicalSourceFromDir(t, source)
+	projectRoot := t.TempDir()
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+
+	methodologyPath := filepath.Join(projectRoot, ".methodology")
+	spirePath := filepath.Join(methodologyPath, "agents", "SPIRE.md")
+	auditorPath := filepath.Join(methodologyPath, "skills", "spec-auditor.md")
+	writeFile(t, filepath.Join(source, "agents", "SPIRE.md"), "# SPIRE v2\n")
+
+	if err := os.Remove(spirePath); err != nil {
+		t.Fatalf("remove SPIRE.md: %v", err)
+	}
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if code := RunInit(context.Background(), []string{"--repair"}, projectRoot, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", code, stderr.String())
+	}
+	if got := string(mustReadFile(t, spirePath)); got != "# SPIRE\n" {
+		t.Fatalf("SPIRE.md should come from the cached recorded payload, got %q", got)
+	}
+
+	// Without the cached payload only files upstream still has unchanged can be restored.
+	t.Setenv("SPIRE_CACHE_DIR", t.TempDir())
+	for _, path := range []string{spirePath, auditorPath} {
+		if err := os.Remove(path); err != nil {
+			t.Fatalf("remove %s: %v", path, err)
+		}
+	}
+	stdout.Reset()
+	stderr.Reset()
+	if code := RunInit(context.Background(), []string{"--repair"}, projectRoot, &stdout, &stderr); code != 1 {
+		t.Fatalf("exit code: got %d, want 1", code)
+	}
+	if !strings.Contains(stdout.String(), "restored: skills/spec-auditor.md") {
+		t.Fatalf("stdout: %q", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "cannot restore agents/SPIRE.md") {
+		t.Fatalf("stderr: %q", stderr.String())
+	}
+	if _, err := os.Stat(spirePath); !os.IsNotExist(err) {
+		t.Fatalf("SPIRE.md should not be restored from a different payload, stat err=%v", err)
+	}
+}
+
+func TestRunInitRepairRequiresSyncState(t *testing.T) {
+	configureCanonicalSourceFromDir(t, createMethodologySource(t))
+	projectRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(projectRoot, ".methodology"), 0o755); err != nil {
+		t.Fatalf("mkdir .methodology: %v", err)
+	}
+
+	var stderr bytes.Buffer
+	exitCode := RunInit(context.Background(), []string{"--repair"}, projectRoot, &bytes.Buffer{}, &stderr)
+
+	if exitCode != 1 {
+		t.Fatalf("exit code: got %d, want 1", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "use spire init --reinit") {
+		t.Fatalf("stderr: %q", stderr.String())
+	}
+}
+
 func TestRunInitDoesNotOverwriteExistingProjectedFiles(t *testing.T) {
 	source := createMethodologySource(t)
 	configureCanonicalSourceFromDir(t, source)
@@ -85,7 +258,7 @@ func TestRunInitDoesNotOverwriteExistingProjectedFiles(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunInit(nil, projectRoot, &stdout, &stderr)
+	exitCode := RunInit(context.Background(), nil, projectRoot, &stdout, &stderr)
 
 	if exitCode != 0 {
 		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
@@ -130,7 +303,7 @@ func TestRunInitGitignoreEntryAddedOnlyOnce(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunInit(nil, projectRoot, &stdout, &stderr)
+	exitCode := RunInit(context.Background(), nil, projectRoot, &stdout, &stderr)
 
 	if exitCode != 0 {
 		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
@@ -149,22 +322,144 @@ func TestRunInitGitignoreEntryAddedOnlyOnce(t *testing.T) {
 
 func TestRunInitFailsWhenSourceDownloadFails(t *testing.T) {
 	projectRoot := t.TempDir()
+	t.Setenv("SPIRE_CACHE_DIR", t.TempDir())
 
 	restore := methodology.SetCanonicalSourceForTesting("niparis/spire", "main", "https://127.0.0.1:1/not-available.tar.gz")
 	t.Cleanup(restore)
+	t.Cleanup(methodology.SetEmbeddedPayloadForTesting(fstest.MapFS{}, "test"))
+	t.Cleanup(download.SetBackoffForTesting(time.Millisecond))
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunInit(context.Background(), nil, projectRoot, &stdout, &stderr)
+
+	if exitCode != 1 {
+		t.Fatalf("exit code: got %d, want 1", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "failed to initialize methodology payload") {
+		t.Fatalf("stderr: %q", stderr.String())
+	}
+}
+
+func TestRunInitInterruptedLeavesProjectUntouched(t *testing.T) {
+	projectRoot := t.TempDir()
+	configureCanonicalSourceFromDir(t, createMethodologySource(t))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunInit(nil, projectRoot, &stdout, &stderr)
+	exitCode := RunInit(ctx, nil, projectRoot, &stdout, &stderr)
+
+	if exitCode != 1 {
+		t.Fatalf("exit code: got %d, want 1", exitCode)
+	}
+	if strings.Contains(stderr.String(), "falling back to embedded") {
+		t.Fatalf("interrupted init should not fall back: %q", stderr.String())
+	}
+
+	entries, err := os.ReadDir(projectRoot)
+	if err != nil {
+		t.Fatalf("read project root: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("project root should be empty, found %v", entries)
+	}
+}
+
+func TestRunInitFallsBackToEmbeddedPayload(t *testing.T) {
+	projectRoot := t.TempDir()
+	t.Setenv("SPIRE_CACHE_DIR", t.TempDir())
+
+	t.Cleanup(methodology.SetCanonicalSourceForTesting("niparis/spire", "main", "https://127.0.0.1:1/not-available.tar.gz"))
+	t.Cleanup(download.SetBackoffForTesting(time.Millisecond))
+	t.Cleanup(methodology.SetEmbeddedPayloadForTesting(os.DirFS(createMethodologySource(t)), "1.2.3"))
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunInit(context.Background(), nil, projectRoot, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "warning: falling back to embedded methodology payload (version 1.2.3)") {
+		t.Fatalf("stderr: %q", stderr.String())
+	}
+	assertFileExists(t, filepath.Join(projectRoot, ".methodology", "agents", "SPIRE.md"))
+	assertFileContains(t, filepath.Join(projectRoot, ".methodology", ".spire-source.json"), "\"embedded\": true")
+	assertFileExists(t, filepath.Join(projectRoot, "AGENTS.md"))
+}
+
+func TestRunInitDoesNotFallBackOnCorruptPayload(t *testing.T) {
+	t.Setenv("SPIRE_CACHE_DIR", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("not a tarball"))
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(methodology.SetCanonicalSourceForTesting("niparis/spire", "main", server.URL+"/tarball.tar.gz"))
+	t.Cleanup(methodology.SetEmbeddedPayloadForTesting(os.DirFS(createMethodologySource(t)), "1.2.3"))
+
+	var stderr bytes.Buffer
+	exitCode := RunInit(context.Background(), nil, t.TempDir(), &bytes.Buffer{}, &stderr)
 
 	if exitCode != 1 {
 		t.Fatalf("exit code: got %d, want 1", exitCode)
 	}
+	if strings.Contains(stderr.String(), "falling back to embedded") {
+		t.Fatalf("a corrupt payload should not fall back: %q", stderr.String())
+	}
 	if !strings.Contains(stderr.String(), "failed to initialize methodology payload") {
 		t.Fatalf("stderr: %q", stderr.String())
 	}
 }
 
+func TestRunInitEmbeddedSourceThenUpdateMovesOnline(t *testing.T) {
+	source := createMethodologySource(t)
+	configureCanonicalSourceFromDir(t, source)
+	embedded := createMethodologySource(t)
+	writeFile(t, filepath.Join(embedded, "agents", "SPIRE.md"), "# SPIRE embedded\n")
+	t.Cleanup(methodology.SetEmbeddedPayloadForTesting(os.DirFS(embedded), "1.2.3"))
+	projectRoot := t.TempDir()
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunInit(context.Background(), []string{"--source", "embedded"}, projectRoot, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "source: embedded payload (version 1.2.3)") {
+		t.Fatalf("stdout: %q", stdout.String())
+	}
+	assertFileContains(t, filepath.Join(projectRoot, ".methodology", "agents", "SPIRE.md"), "SPIRE embedded")
+
+	stdout.Reset()
+	if code := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr); code != 0 {
+		t.Fatalf("update failed with code %d, stderr=%q", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "moving from embedded payload (version 1.2.3) to niparis/spire@main") {
+		t.Fatalf("stdout: %q", stdout.String())
+	}
+	assertFileNotContains(t, filepath.Join(projectRoot, ".methodology", "agents", "SPIRE.md"), "embedded")
+	assertFileNotContains(t, filepath.Join(projectRoot, ".methodology", ".spire-source.json"), "\"embedded\"")
+}
+
+func TestRunInitBundledEmbeddedPayload(t *testing.T) {
+	projectRoot := t.TempDir()
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunInit(context.Background(), []string{"--source", "embedded"}, projectRoot, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	assertFileExists(t, filepath.Join(projectRoot, ".methodology", "project_root", "manifest.json"))
+	assertFileExists(t, filepath.Join(projectRoot, ".methodology", "project_root", ".opencode", "agents", "build-feature.md"))
+	assertFileExists(t, filepath.Join(projectRoot, ".opencode", "agents", "featureplanner.md"))
+}
+
 func createMethodologySource(t *testing.T) string {
 	t.Helper()
 
@@ -180,7 +475,7 @@ func createMethodologySource(t *testing.T) string {
 	writeFile(t, filepath.Join(root, "subagents", "investigator.md"), "---\nmode: subagent\n---\nRead .methodology/agents/INVESTIGATOR.md and .methodology/agents/SPIRE.md\n")
 	writeFile(t, filepath.Join(root, "project_root", ".opencode", "agents", "productengineer.md"), "---\nmode: primary\n---\nRead .methodology/agents/ARCHITECTURE.md and specs/PRODUCT.md\n")
 	writeFile(t, filepath.Join(root, "project_root", "manifest.json"), `{
-  "version": 1,
+  "version": 2,
   "mappings": [
     {
       "source": "project_root/local_agents.md",
@@ -200,35 +495,35 @@ func createMethodologySource(t *testing.T) string {
       "source": "subagents/featureplanner.md",
       "destination": ".opencode/agents/featureplanner.md",
       "on_init": "if_missing",
-      "on_update": "never_overwrite",
+      "on_update": "always_overwrite",
       "notify_if_source_changed": true
     },
     {
       "source": "subagents/verifier.md",
       "destination": ".opencode/agents/verifier.md",
       "on_init": "if_missing",
-      "on_update": "never_overwrite",
+      "on_update": "always_overwrite",
       "notify_if_source_changed": true
     },
     {
       "source": "subagents/docs-writer.md",
       "destination": ".opencode/agents/docs-writer.md",
       "on_init": "if_missing",
-      "on_update": "never_overwrite",
+      "on_update": "always_overwrite",
       "notify_if_source_changed": true
     },
     {
       "source": "subagents/investigator.md",
       "destination": ".opencode/agents/investigator.md",
       "on_init": "if_missing",
-      "on_update": "never_overwrite",
+      "on_update": "always_overwrite",
       "notify_if_source_changed": true
     },
     {
       "source": "project_root/.opencode/agents/productengineer.md",
       "destination": ".opencode/agents/productengineer.md",
       "on_init": "if_missing",
-      "on_update": "never_overwrite",
+      "on_update": "always_overwrite",
       "notify_if_source_changed": true
     }
   ]
@@ -240,6 +535,8 @@ func createMethodologySource(t *testing.T) string {
 func configureCanonicalSourceFromDir(t *testing.T, sourceDir string) {
 	t.Helper()
 
+	t.Setenv("SPIRE_CACHE_DIR", t.TempDir())
+
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/tarball.tar.gz" {
 			http.NotFound(w, r)
@@ -349,3 +646,187 @@ func assertFileNotContains(t *testing.T, path string, want string) {
 		t.Fatalf("file %s unexpectedly contains %q; got %q", path, want, string(data))
 	}
 }
+
+func TestRunInitManagedBlockInsertedIntoExistingFile(t *testing.T) {
+	source := createMethodologySource(t)
+	setManifestPolicy(t, source, "AGENTS.md", "managed_block", "managed_block")
+	writeFile(t, filepath.Join(source, "project_root", "local_agents.md"), "# Project\n\n<!-- spire:begin -->\nspire rules\n<!-- spire:end -->\n")
+	configureCanonicalSourceFromDir(t, source)
+	projectRoot := t.TempDir()
+
+	writeFile(t, filepath.Join(projectRoot, "AGENTS.md"), "# existing\nkeep me\n")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunInit(context.Background(), nil, projectRoot, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "inserted block: AGENTS.md") {
+		t.Fatalf("stdout: %q", stdout.String())
+	}
+
+	want := "# existing\nkeep me\n\n<!-- spire:begin -->\nspire rules\n<!-- spire:end -->\n"
+	if got := string(mustReadFile(t, filepath.Join(projectRoot, "AGENTS.md"))); got != want {
+		t.Fatalf("AGENTS.md: got %q, want %q", got, want)
+	}
+}
+
+func TestRunInitProfileSelectsConditionalMappings(t *testing.T) {
+	source := createMethodologySource(t)
+	writeFile(t, filepath.Join(source, "subagents", "go-reviewer.md"), "---\nmode: subagent\n---\nGo review\n")
+	writeFile(t, filepath.Join(source, "subagents", "ui-reviewer.md"), "---\nmode: subagent\n---\nUI review\n")
+	addManifestMapping(t, source, map[string]any{
+		"source": "subagents/go-reviewer.md", "destination": ".opencode/agents/go-reviewer.md",
+		"on_init": "if_missing", "on_update": "if_missing",
+		"when": map[string]any{"profile": "backend", "file_exists": "go.mod"},
+	})
+	addManifestMapping(t, source, map[string]any{
+		"source": "subagents/ui-reviewer.md", "destination": ".opencode/agents/ui-reviewer.md",
+		"on_init": "if_missing", "on_update": "if_missing",
+		"when": map[string]any{"profile": "frontend"},
+	})
+	configureCanonicalSourceFromDir(t, source)
+	projectRoot := t.TempDir()
+	writeFile(t, filepath.Join(projectRoot, "go.mod"), "module example.com/demo\n")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunInit(context.Background(), []string{"--profile", "backend"}, projectRoot, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+
+	assertFileExists(t, filepath.Join(projectRoot, ".opencode", "agents", "go-reviewer.md"))
+	if _, err := os.Stat(filepath.Join(projectRoot, ".opencode", "agents", "ui-reviewer.md")); !os.IsNotExist(err) {
+		t.Fatalf("ui-reviewer.md should not be projected for backend profile, stat err=%v", err)
+	}
+	assertFileContains(t, filepath.Join(projectRoot, ".spire.json"), "\"profile\": \"backend\"")
+
+	if err := os.Remove(filepath.Join(projectRoot, ".opencode", "agents", "go-reviewer.md")); err != nil {
+		t.Fatalf("remove go-reviewer.md: %v", err)
+	}
+	if code := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("update failed with code %d", code)
+	}
+	assertFileExists(t, filepath.Join(projectRoot, ".opencode", "agents", "go-reviewer.md"))
+
+	// A fresh clone has the committed settings but no .methodology.
+	for _, path := range []string{".methodology", ".opencode"} {
+		if err := os.RemoveAll(filepath.Join(projectRoot, path)); err != nil {
+			t.Fatalf("remove %s: %v", path, err)
+		}
+	}
+	stdout.Reset()
+	if code := RunInit(context.Background(), nil, projectRoot, &stdout, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init in fresh clone failed with code %d", code)
+	}
+	if !strings.Contains(stdout.String(), "profile: backend\n") {
+		t.Fatalf("stdout: %q", stdout.String())
+	}
+	assertFileExists(t, filepath.Join(projectRoot, ".opencode", "agents", "go-reviewer.md"))
+}
+
+func TestRunInitReinitMovesLegacyProjectSettingsToRoot(t *testing.T) {
+	source := createMethodologySource(t)
+	writeFile(t, filepath.Join(source, "subagents", "go-reviewer.md"), "---\nmode: subagent\n---\nGo review\n")
+	addManifestMapping(t, source, map[string]any{
+		"source": "subagents/go-reviewer.md", "destination": ".opencode/agents/go-reviewer.md",
+		"on_init": "if_missing", "on_update": "if_missing",
+		"when": map[string]any{"profile": "backend"},
+	})
+	configureCanonicalSourceFromDir(t, source)
+	projectRoot := t.TempDir()
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+
+	// Installs from before the root settings file kept them in .methodology.
+	legacyPath := filepath.Join(projectRoot, ".methodology", ".spire-project.json")
+	writeFile(t, legacyPath, "{\n  \"profile\": \"backend\"\n}")
+
+	var stderr bytes.Buffer
+	if code := RunInit(context.Background(), []string{"--reinit"}, projectRoot, &bytes.Buffer{}, &stderr); code != 0 {
+		t.Fatalf("reinit exit code: got %d, stderr=%q", code, stderr.String())
+	}
+
+	assertFileContains(t, filepath.Join(projectRoot, ".spire.json"), "\"profile\": \"backend\"")
+	if _, err := os.Stat(legacyPath); !os.IsNotExist(err) {
+		t.Fatalf("legacy settings should be removed, stat err=%v", err)
+	}
+	assertFileExists(t, filepath.Join(projectRoot, ".opencode", "agents", "go-reviewer.md"))
+}
+
+func TestRunInitTargetsProjectEachRuntime(t *testing.T) {
+	source := createMethodologySource(t)
+	writeFile(t, filepath.Join(source, "subagents", "featureplanner.md"), "---\ndescription: Plans features.\nmode: subagent\n---\nPlan it\n")
+	addManifestTarget(t, source, "claude", map[string]any{
+		"source": "subagents/*.md", "destination": ".claude/agents",
+		"on_init": "overwrite_if_unmodified", "on_update": "overwrite_if_unmodified",
+		"frontmatter": map[string]any{"keep": []string{"description"}, "set": map[string]string{"name": "{{ .Name }}"}},
+	})
+	addManifestTarget(t, source, "cursor", map[string]any{
+		"source": "subagents/*.md", "destination": ".cursor/rules", "extension": ".mdc",
+		"on_init": "overwrite_if_unmodified", "on_update": "overwrite_if_unmodified",
+	})
+	configureCanonicalSourceFromDir(t, source)
+	projectRoot := t.TempDir()
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunInit(context.Background(), []string{"--target", "opencode,claude"}, projectRoot, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "targets: opencode, claude") {
+		t.Fatalf("stdout: %q", stdout.String())
+	}
+
+	want := "---\ndescription: Plans features.\nname: featureplanner\n---\nPlan it\n"
+	if got := string(mustReadFile(t, filepath.Join(projectRoot, ".claude", "agents", "featureplanner.md"))); got != want {
+		t.Fatalf("claude agent: got %q, want %q", got, want)
+	}
+	assertFileExists(t, filepath.Join(projectRoot, ".opencode", "agents", "featureplanner.md"))
+	if _, err := os.Stat(filepath.Join(projectRoot, ".cursor")); !os.IsNotExist(err) {
+		t.Fatalf(".cursor should not be projected, stat err=%v", err)
+	}
+
+	writeFile(t, filepath.Join(source, "subagents", "featureplanner.md"), "---\ndescription: Plans features.\nmode: subagent\n---\nPlan it carefully\n")
+	if code := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("update failed with code %d", code)
+	}
+	assertFileContains(t, filepath.Join(projectRoot, ".claude", "agents", "featureplanner.md"), "Plan it carefully")
+	assertFileContains(t, filepath.Join(projectRoot, ".opencode", "agents", "featureplanner.md"), "Plan it carefully")
+}
+
+func TestRunInitRejectsUnknownTarget(t *testing.T) {
+	configureCanonicalSourceFromDir(t, createMethodologySource(t))
+	projectRoot := t.TempDir()
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunInit(context.Background(), []string{"--target", "vim"}, projectRoot, &stdout, &stderr)
+
+	if exitCode != 1 {
+		t.Fatalf("exit code: got %d, want 1", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "unknown target \"vim\"") {
+		t.Fatalf("stderr: %q", stderr.String())
+	}
+}
+
+func TestRunInitRejectsUnknownFlag(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunInit(context.Background(), []string{"--bogus"}, t.TempDir(), &stdout, &stderr)
+
+	if exitCode != 1 {
+		t.Fatalf("exit code: got %d, want 1", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "flag provided but not defined: -bogus") {
+		t.Fatalf("stderr: %q", stderr.String())
+	}
+}
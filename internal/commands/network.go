@@ -0,0 +1,20 @@
+package commands
+
+import (
+	"io"
+	"time"
+
+	"opencode-spire/internal/download"
+	"opencode-spire/internal/methodology"
+)
+
+func progressOutput(stderr io.Writer) io.Writer {
+	if download.IsTerminal(stderr) {
+		return stderr
+	}
+	return nil
+}
+
+func fetchOptions(offline bool, timeout time.Duration, stderr io.Writer) methodology.FetchOptions {
+	return methodology.FetchOptions{Offline: offline, Timeout: timeout, Progress: progressOutput(stderr), Warnings: stderr}
+}
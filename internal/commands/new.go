@@ -2,6 +2,7 @@ package commands
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -15,7 +16,16 @@ import (
 
 var featureFilePattern = regexp.MustCompile(`^feature-(\d+)-(.+)\.md$`)
 
-func RunNew(args []string, projectRoot string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+func RunNew(ctx context.Context, args []string, projectRoot string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("new", stderr)
+	if done, code := parseFlags(flags, args, stdout); done {
+		return code
+	}
+	if flags.NArg() > 0 {
+		printUsage(stderr, "new")
+		return 1
+	}
+
 	nextNum, err := nextFeatureNumber(projectRoot)
 	if err != nil {
 		fmt.Fprintf(stderr, "failed to determine next feature number: %v\n", err)
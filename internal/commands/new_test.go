@@ -2,6 +2,7 @@ package commands
 
 import (
 	"bytes"
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,7 +14,7 @@ func TestRunNewFirstFeatureUses001(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunNew(nil, projectRoot, strings.NewReader("User Auth\n"), &stdout, &stderr)
+	exitCode := RunNew(context.Background(), nil, projectRoot, strings.NewReader("User Auth\n"), &stdout, &stderr)
 
 	if exitCode != 0 {
 		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
@@ -30,7 +31,7 @@ func TestRunNewUsesMaxPlusOneNumbering(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunNew(nil, projectRoot, strings.NewReader("Three\n"), &stdout, &stderr)
+	exitCode := RunNew(context.Background(), nil, projectRoot, strings.NewReader("Three\n"), &stdout, &stderr)
 
 	if exitCode != 0 {
 		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
@@ -46,7 +47,7 @@ func TestRunNewGapUsesMaxPlusOne(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunNew(nil, projectRoot, strings.NewReader("Four\n"), &stdout, &stderr)
+	exitCode := RunNew(context.Background(), nil, projectRoot, strings.NewReader("Four\n"), &stdout, &stderr)
 
 	if exitCode != 0 {
 		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
@@ -60,7 +61,7 @@ func TestRunNewSanitizesName(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunNew(nil, projectRoot, strings.NewReader("My Fancy FEATURE\n"), &stdout, &stderr)
+	exitCode := RunNew(context.Background(), nil, projectRoot, strings.NewReader("My Fancy FEATURE\n"), &stdout, &stderr)
 
 	if exitCode != 0 {
 		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
@@ -74,7 +75,7 @@ func TestRunNewEmptyNameAborts(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunNew(nil, projectRoot, strings.NewReader("   \n"), &stdout, &stderr)
+	exitCode := RunNew(context.Background(), nil, projectRoot, strings.NewReader("   \n"), &stdout, &stderr)
 
 	if exitCode != 1 {
 		t.Fatalf("exit code: got %d, want 1", exitCode)
@@ -90,7 +91,7 @@ func TestRunNewDuplicateSpecAborts(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunNew(nil, projectRoot, strings.NewReader("User Auth\n"), &stdout, &stderr)
+	exitCode := RunNew(context.Background(), nil, projectRoot, strings.NewReader("User Auth\n"), &stdout, &stderr)
 
 	if exitCode != 1 {
 		t.Fatalf("exit code: got %d, want 1", exitCode)
@@ -106,7 +107,7 @@ func TestRunNewTemplateSubstitution(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunNew(nil, projectRoot, strings.NewReader("User Auth\n"), &stdout, &stderr)
+	exitCode := RunNew(context.Background(), nil, projectRoot, strings.NewReader("User Auth\n"), &stdout, &stderr)
 
 	if exitCode != 0 {
 		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
@@ -0,0 +1,164 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"opencode-spire/internal/session"
+	"opencode-spire/internal/verification"
+)
+
+func declarePRFlags(flags *flag.FlagSet) *string {
+	return flags.String("output", "", "write the description to `file` instead of stdout")
+}
+
+func RunPR(ctx context.Context, args []string, projectRoot string, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("pr", stderr)
+	output := declarePRFlags(flags)
+	if done, code := parseFlags(flags, args, stdout); done {
+		return code
+	}
+	if flags.NArg() != 1 {
+		printUsage(stderr, "pr")
+		return 1
+	}
+
+	feature, err := resolveFeature(projectRoot, flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+
+	report, err := verification.Load(projectRoot, feature.Slug)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to read verification report: %v\n", err)
+		return 1
+	}
+	if report == nil {
+		fmt.Fprintf(stderr, "no %s for %s; run verification first\n", verification.Filename, feature.Slug)
+		return 1
+	}
+	if !report.Ready() {
+		verdict := report.Verdict
+		if verdict == "" {
+			verdict = "missing"
+		}
+		fmt.Fprintf(stderr, "refusing to write a PR description for %s: verdict is %s, want %s\n", feature.Slug, verdict, verification.VerdictReady)
+		return 1
+	}
+
+	parsed, err := session.Load(projectRoot, feature.Slug)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to read session: %v\n", err)
+		return 1
+	}
+
+	body, err := prDescription(projectRoot, feature, *report, parsed)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to build PR description: %v\n", err)
+		return 1
+	}
+
+	if *output == "" {
+		fmt.Fprint(stdout, body)
+		return 0
+	}
+	path := *output
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectRoot, path)
+	}
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		fmt.Fprintf(stderr, "failed to write PR description: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "wrote PR description for %s to %s\n", feature.Slug, *output)
+	return 0
+}
+
+// prDescription fills the PR description template from PRODUCT.md with the
+// feature's spec, traceability matrix and SESSION.md sections. parsed may be
+// nil when the feature has no session log.
+func prDescription(projectRoot string, feature featureEntry, report verification.Report, parsed *session.Session) (string, error) {
+	specPath := filepath.ToSlash(filepath.Join("specs", fmt.Sprintf("feature-%s-%s.md", feature.Number, feature.Name)))
+	changesDir := filepath.ToSlash(filepath.Join("changes", feature.Slug))
+
+	title, err := specTitle(filepath.Join(projectRoot, specPath))
+	if err != nil {
+		return "", err
+	}
+	if title == "" {
+		title = feature.Name
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "## Feature: %s\n", title)
+	fmt.Fprintf(&body, "Spec: %s\n", specPath)
+	if exists, err := pathExists(filepath.Join(projectRoot, changesDir, "PLAN.md")); err != nil {
+		return "", err
+	} else if exists {
+		fmt.Fprintf(&body, "Plan: %s/PLAN.md\n", changesDir)
+	}
+	fmt.Fprintf(&body, "Verification: %s/%s\n", changesDir, verification.Filename)
+
+	writePRSection(&body, "AC Coverage", report.Matrix)
+	writePRSection(&body, "Decisions Made", sessionSectionLines(parsed, session.SectionDecisions))
+	writePRSection(&body, "Discovered Constraints", sessionSectionLines(parsed, session.SectionConstraints))
+	return body.String(), nil
+}
+
+func writePRSection(w io.Writer, heading string, lines []string) {
+	fmt.Fprintf(w, "\n## %s\n", heading)
+	if len(lines) == 0 {
+		fmt.Fprintln(w, "None recorded.")
+		return
+	}
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+}
+
+func sessionSectionLines(parsed *session.Session, name string) []string {
+	if parsed == nil {
+		return nil
+	}
+	section, ok := parsed.Section(name)
+	if !ok {
+		return nil
+	}
+
+	var lines []string
+	for _, line := range section.Content() {
+		lines = append(lines, line.Text)
+	}
+	return lines
+}
+
+// specTitle returns the name after `# Spec:` in the spec's first heading.
+func specTitle(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("open spec %q: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "# ") {
+			return strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(line, "# "), "Spec:")), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("scan spec %q: %w", path, err)
+	}
+	return "", nil
+}
@@ -0,0 +1,423 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Command describes a spire subcommand for dispatch, `spire help` and shell
+// completion.
+type Command struct {
+	Name     string
+	Summary  string
+	Usage    string
+	Examples []string
+
+	words func() []string
+	flags func(*flag.FlagSet)
+	run   func(context.Context, Invocation) int
+}
+
+// Invocation carries everything a command may need from the process.
+type Invocation struct {
+	Args        []string
+	Dir         string
+	Version     string
+	Stdin       io.Reader
+	Interactive bool
+	Stdout      io.Writer
+	Stderr      io.Writer
+}
+
+func Registry() []Command {
+	return []Command{
+		{
+			Name:    "init",
+			Summary: "Initialize project methodology",
+			Usage:   "spire init [--profile <name>] [--target <a,b>] [--reinit | --repair] [--offline] [--source online|embedded] [--timeout <duration>]",
+			Examples: []string{
+				"spire init",
+				"spire init --target opencode,claude",
+				"spire init --repair",
+			},
+			flags: func(flags *flag.FlagSet) { declareInitFlags(flags) },
+			run: func(ctx context.Context, inv Invocation) int {
+				return RunInit(ctx, inv.Args, inv.Dir, inv.Stdout, inv.Stderr)
+			},
+		},
+		{
+			Name:     "update",
+			Summary:  "Update local methodology",
+			Usage:    "spire update [--offline] [--timeout <duration>]",
+			Examples: []string{"spire update", "spire update --offline"},
+			flags:    func(flags *flag.FlagSet) { declareUpdateFlags(flags) },
+			run: func(ctx context.Context, inv Invocation) int {
+				return RunUpdate(ctx, inv.Args, inv.Dir, inv.Stdin, inv.Interactive, inv.Stdout, inv.Stderr)
+			},
+		},
+		{
+			Name:     "deinit",
+			Summary:  "Remove spire scaffolding from the project",
+			Usage:    "spire deinit [--force] [--dry-run]",
+			Examples: []string{"spire deinit --dry-run", "spire deinit --force"},
+			flags:    func(flags *flag.FlagSet) { declareDeinitFlags(flags) },
+			run: func(ctx context.Context, inv Invocation) int {
+				return RunDeinit(ctx, inv.Args, inv.Dir, inv.Stdout, inv.Stderr)
+			},
+		},
+		{
+			Name:     "new",
+			Summary:  "Create a new feature spec",
+			Usage:    "spire new",
+			Examples: []string{"spire new"},
+			run: func(ctx context.Context, inv Invocation) int {
+				return RunNew(ctx, inv.Args, inv.Dir, inv.Stdin, inv.Stdout, inv.Stderr)
+			},
+		},
+		{
+			Name:    "feature",
+			Summary: "Rename, renumber or remove a feature and all its artifacts",
+			Usage:   "spire feature rename <feature> <new-name> | spire feature renumber <feature> <n> | spire feature rm <feature> [--force] [--dry-run]",
+			Examples: []string{
+				"spire feature rename 003 user-auth",
+				"spire feature renumber 003-user-auth 5 --dry-run",
+				"spire feature rm 005 --force",
+			},
+			words: func() []string { return []string{"rename", "renumber", "rm"} },
+			flags: func(flags *flag.FlagSet) { declareFeatureFlags(flags) },
+			run: func(ctx context.Context, inv Invocation) int {
+				return RunFeature(ctx, inv.Args, inv.Dir, inv.Stdin, inv.Interactive, inv.Stdout, inv.Stderr)
+			},
+		},
+		{
+			Name:     "status",
+			Summary:  "Show feature status table",
+			Usage:    "spire status [--no-git]",
+			Examples: []string{"spire status", "spire status --no-git"},
+			flags:    func(flags *flag.FlagSet) { declareStatusFlags(flags) },
+			run: func(ctx context.Context, inv Invocation) int {
+				return RunStatus(ctx, inv.Args, inv.Dir, inv.Stdout, inv.Stderr)
+			},
+		},
+		{
+			Name:     "approve",
+			Summary:  "Record Gate 2 approval of a feature's plan and tasks",
+			Usage:    "spire approve <feature> [--plan | --tasks]",
+			Examples: []string{"spire approve 003", "spire approve 003 --plan"},
+			flags:    func(flags *flag.FlagSet) { declareApproveFlags(flags) },
+			run: func(ctx context.Context, inv Invocation) int {
+				return RunApprove(ctx, inv.Args, inv.Dir, inv.Stdout, inv.Stderr)
+			},
+		},
+		{
+			Name:    "tasks",
+			Summary: "List, check off and pick the next feature task",
+			Usage:   "spire tasks <feature> | spire tasks done <feature> <task> [--commit <sha>] | spire tasks next [feature]",
+			Examples: []string{
+				"spire tasks 003",
+				"spire tasks done 003 T3 --commit abc1234",
+				"spire tasks next",
+			},
+			words: func() []string { return []string{"done", "next"} },
+			flags: func(flags *flag.FlagSet) { declareTasksFlags(flags) },
+			run: func(ctx context.Context, inv Invocation) int {
+				return RunTasks(ctx, inv.Args, inv.Dir, inv.Stdout, inv.Stderr)
+			},
+		},
+		{
+			Name:     "session",
+			Summary:  "Check a feature's SESSION.md for continuity gaps",
+			Usage:    "spire session check <feature> [--max-age <duration>] [--escalations]",
+			Examples: []string{"spire session check 003", "spire session check 003 --max-age 48h", "spire session check 003 --escalations"},
+			words:    func() []string { return []string{"check"} },
+			flags:    func(flags *flag.FlagSet) { declareSessionFlags(flags) },
+			run: func(ctx context.Context, inv Invocation) int {
+				return RunSession(ctx, inv.Args, inv.Dir, inv.Stdout, inv.Stderr)
+			},
+		},
+		{
+			Name:     "pr",
+			Summary:  "Print a PR description for a verified feature",
+			Usage:    "spire pr <feature> [--output <file>]",
+			Examples: []string{"spire pr 003", "spire pr 003 --output pr.md", "spire pr 003 | gh pr create --body-file -"},
+			flags:    func(flags *flag.FlagSet) { declarePRFlags(flags) },
+			run: func(ctx context.Context, inv Invocation) int {
+				return RunPR(ctx, inv.Args, inv.Dir, inv.Stdout, inv.Stderr)
+			},
+		},
+		{
+			Name:    "gate",
+			Summary: "Check methodology gates for CI",
+			Usage:   "spire gate check [--feature <feature>] [--gate <n>] [--base <ref>] [--format text|github] [--junit <file>]",
+			Examples: []string{
+				"spire gate check",
+				"spire gate check --feature 003 --gate 1",
+				"spire gate check --base origin/main --format github --junit gates.xml",
+			},
+			words: func() []string { return []string{"check"} },
+			flags: func(flags *flag.FlagSet) { declareGateFlags(flags) },
+			run: func(ctx context.Context, inv Invocation) int {
+				return RunGate(ctx, inv.Args, inv.Dir, inv.Stdout, inv.Stderr)
+			},
+		},
+		{
+			Name:     "validate",
+			Summary:  "Validate opencode.json and agent files",
+			Usage:    "spire validate",
+			Examples: []string{"spire validate"},
+			run: func(ctx context.Context, inv Invocation) int {
+				return RunValidate(ctx, inv.Args, inv.Dir, inv.Stdout, inv.Stderr)
+			},
+		},
+		{
+			Name:     "cache",
+			Summary:  "List or prune cached methodology payloads",
+			Usage:    "spire cache <list|prune> [--all]",
+			Examples: []string{"spire cache list", "spire cache prune --all"},
+			words:    func() []string { return []string{"list", "prune"} },
+			flags:    func(flags *flag.FlagSet) { declareCachePruneFlags(flags) },
+			run: func(ctx context.Context, inv Invocation) int {
+				return RunCache(ctx, inv.Args, inv.Stdout, inv.Stderr)
+			},
+		},
+		{
+			Name:     "upgrade",
+			Summary:  "Upgrade spire executable",
+			Usage:    "spire upgrade [--timeout <duration>]",
+			Examples: []string{"spire upgrade"},
+			flags:    func(flags *flag.FlagSet) { declareUpgradeFlags(flags) },
+			run: func(ctx context.Context, inv Invocation) int {
+				return RunUpgrade(ctx, inv.Args, inv.Version, inv.Stdout, inv.Stderr)
+			},
+		},
+		{
+			Name:     "completion",
+			Summary:  "Print a shell completion script",
+			Usage:    "spire completion <bash|zsh|fish>",
+			Examples: []string{"source <(spire completion bash)", "spire completion fish > ~/.config/fish/completions/spire.fish"},
+			words:    func() []string { return completionShells },
+			run: func(ctx context.Context, inv Invocation) int {
+				return RunCompletion(ctx, inv.Args, inv.Stdout, inv.Stderr)
+			},
+		},
+		{
+			Name:     "help",
+			Summary:  "Show help for a command",
+			Usage:    "spire help [command]",
+			Examples: []string{"spire help init"},
+			words:    commandNames,
+			run: func(ctx context.Context, inv Invocation) int {
+				return RunHelp(ctx, inv.Args, inv.Stdout, inv.Stderr)
+			},
+		},
+	}
+}
+
+func Lookup(name string) (Command, bool) {
+	for _, command := range Registry() {
+		if command.Name == name {
+			return command, true
+		}
+	}
+	return Command{}, false
+}
+
+func (c Command) Run(ctx context.Context, inv Invocation) int {
+	return c.run(ctx, inv)
+}
+
+// Words returns the fixed positional arguments offered by shell completion.
+func (c Command) Words() []string {
+	if c.words == nil {
+		return nil
+	}
+	return c.words()
+}
+
+// FlagSet returns a fresh copy of the command's flags for help and completion.
+func (c Command) FlagSet() *flag.FlagSet {
+	flags := flag.NewFlagSet(c.Name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	if c.flags != nil {
+		c.flags(flags)
+	}
+	return flags
+}
+
+func RunHelp(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	switch len(args) {
+	case 0:
+		PrintHelp(stdout)
+		return 0
+	case 1:
+		command, ok := Lookup(args[0])
+		if !ok {
+			fmt.Fprintf(stderr, "unknown command: %s\n", args[0])
+			return 1
+		}
+		writeCommandHelp(stdout, command)
+		return 0
+	default:
+		printUsage(stderr, "help")
+		return 1
+	}
+}
+
+func PrintHelp(w io.Writer) {
+	fmt.Fprintln(w, "spire - SDD methodology CLI")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintln(w, "  spire <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, command := range Registry() {
+		fmt.Fprintf(writer, "  %s\t%s\n", command.Name, command.Summary)
+	}
+	writer.Flush()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Flags:")
+	fmt.Fprintln(w, "  -h, --help       Show help")
+	fmt.Fprintln(w, "  -v, --version    Show version")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'spire help <command>' for command flags and examples.")
+}
+
+func writeCommandHelp(w io.Writer, command Command) {
+	fmt.Fprintln(w, command.Summary)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintf(w, "  %s\n", command.Usage)
+
+	flags := command.FlagSet()
+	var defined bool
+	flags.VisitAll(func(*flag.Flag) { defined = true })
+	if defined {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Flags:")
+		writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		flags.VisitAll(func(f *flag.Flag) {
+			fmt.Fprintf(writer, "  %s\t%s\n", flagSynopsis(f), flagDescription(f))
+		})
+		writer.Flush()
+	}
+
+	if len(command.Examples) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Examples:")
+		for _, example := range command.Examples {
+			fmt.Fprintf(w, "  %s\n", example)
+		}
+	}
+}
+
+func flagSynopsis(f *flag.Flag) string {
+	if isBoolFlag(f) {
+		return "--" + f.Name
+	}
+	name, _ := flag.UnquoteUsage(f)
+	if name == "" {
+		name = "value"
+	}
+	return fmt.Sprintf("--%s <%s>", f.Name, name)
+}
+
+func flagDescription(f *flag.Flag) string {
+	_, usage := flag.UnquoteUsage(f)
+	if isBoolFlag(f) || f.DefValue == "" {
+		return usage
+	}
+	return fmt.Sprintf("%s (default %s)", usage, f.DefValue)
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	value, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && value.IsBoolFlag()
+}
+
+func commandNames() []string {
+	var names []string
+	for _, command := range Registry() {
+		names = append(names, command.Name)
+	}
+	return names
+}
+
+// newFlagSet builds the flag set a command parses its arguments with. Usage
+// is printed by parseFlags, so the flag package's own dump is silenced.
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {}
+	return flags
+}
+
+// parseFlags parses args and reports whether the command is already done:
+// -h/--help prints the command's help and succeeds, and any other parse error
+// (such as an unknown flag) prints the usage line and fails. Flags may follow
+// positional arguments, as in `spire tasks done 001 T3 --commit abc1234`.
+func parseFlags(flags *flag.FlagSet, args []string, stdout io.Writer) (bool, int) {
+	err := flags.Parse(flagsFirst(flags, args))
+	if err == nil {
+		return false, 0
+	}
+
+	name, _, _ := strings.Cut(flags.Name(), " ")
+	if errors.Is(err, flag.ErrHelp) {
+		printCommandHelp(stdout, name)
+		return true, 0
+	}
+
+	printUsage(flags.Output(), name)
+	return true, 1
+}
+
+// flagsFirst moves positional arguments behind the flags so the flag package,
+// which stops at the first positional, sees every flag.
+func flagsFirst(flags *flag.FlagSet, args []string) []string {
+	var flagArgs []string
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			positional = append(positional, arg)
+			continue
+		}
+
+		flagArgs = append(flagArgs, arg)
+		name := strings.TrimLeft(arg, "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		if f := flags.Lookup(name); f != nil && !isBoolFlag(f) && i+1 < len(args) {
+			i++
+			flagArgs = append(flagArgs, args[i])
+		}
+	}
+
+	if len(positional) == 0 {
+		return flagArgs
+	}
+	return append(append(flagArgs, "--"), positional...)
+}
+
+func printCommandHelp(w io.Writer, name string) {
+	if command, ok := Lookup(name); ok {
+		writeCommandHelp(w, command)
+	}
+}
+
+func printUsage(w io.Writer, name string) {
+	if command, ok := Lookup(name); ok {
+		fmt.Fprintf(w, "usage: %s\n", command.Usage)
+	}
+}
@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunHelpPrintsCommandFlagsAndExamples(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunHelp(context.Background(), []string{"deinit"}, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	for _, want := range []string{"spire deinit [--force] [--dry-run]", "--dry-run", "--force", "Examples:"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("help missing %q: %q", want, stdout.String())
+		}
+	}
+}
+
+func TestRunHelpRejectsUnknownCommand(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunHelp(context.Background(), []string{"frobnicate"}, &stdout, &stderr)
+
+	if exitCode != 1 {
+		t.Fatalf("exit code: got %d, want 1", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "unknown command: frobnicate") {
+		t.Fatalf("stderr: %q", stderr.String())
+	}
+}
+
+func TestRunNewHelpDoesNotPrompt(t *testing.T) {
+	projectRoot := t.TempDir()
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunNew(context.Background(), []string{"--help"}, projectRoot, strings.NewReader("should-not-be-read\n"), &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if strings.Contains(stdout.String(), "Feature name") {
+		t.Fatalf("new --help prompted: %q", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "Create a new feature spec") {
+		t.Fatalf("stdout: %q", stdout.String())
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, "specs")); !os.IsNotExist(err) {
+		t.Fatalf("new --help created specs/, stat err=%v", err)
+	}
+}
+
+func TestRunStatusRejectsUnknownFlag(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunStatus(context.Background(), []string{"--verbose"}, t.TempDir(), &stdout, &stderr)
+
+	if exitCode != 1 {
+		t.Fatalf("exit code: got %d, want 1", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "flag provided but not defined: -verbose") || !strings.Contains(stderr.String(), "usage: spire status") {
+		t.Fatalf("stderr: %q", stderr.String())
+	}
+}
+
+func TestRegistryCommandsHaveUsage(t *testing.T) {
+	for _, command := range Registry() {
+		if command.Summary == "" || !strings.HasPrefix(command.Usage, "spire "+command.Name) || command.run == nil {
+			t.Fatalf("incomplete command %+v", command)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"opencode-spire/internal/git"
+	"opencode-spire/internal/session"
+	"opencode-spire/internal/tasks"
+)
+
+type sessionFlags struct {
+	maxAge      *time.Duration
+	escalations *bool
+}
+
+func declareSessionFlags(flags *flag.FlagSet) sessionFlags {
+	return sessionFlags{
+		maxAge:      flags.Duration("max-age", session.DefaultMaxAge, "how old `Last updated` may be before it counts as stale"),
+		escalations: flags.Bool("escalations", false, "only check the Failure Log circuit breaker"),
+	}
+}
+
+func RunSession(ctx context.Context, args []string, projectRoot string, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("session", stderr)
+	options := declareSessionFlags(flags)
+	if done, code := parseFlags(flags, args, stdout); done {
+		return code
+	}
+
+	positional := flags.Args()
+	if len(positional) != 2 || positional[0] != "check" {
+		printUsage(stderr, "session")
+		return 1
+	}
+	return runSessionCheck(ctx, projectRoot, positional[1], options, stdout, stderr)
+}
+
+func runSessionCheck(ctx context.Context, projectRoot string, ref string, options sessionFlags, stdout io.Writer, stderr io.Writer) int {
+	feature, err := resolveFeature(projectRoot, ref)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+
+	parsed, err := session.Load(projectRoot, feature.Slug)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to read session: %v\n", err)
+		return 1
+	}
+	if parsed == nil {
+		fmt.Fprintf(stderr, "no %s for %s\n", session.Filename, feature.Slug)
+		return 1
+	}
+
+	var issues []session.Issue
+	if *options.escalations {
+		issues = session.CheckEscalations(*parsed)
+	} else {
+		issues = session.Check(*parsed, time.Now(), *options.maxAge)
+		commitIssues, err := verifySessionCommits(ctx, projectRoot, feature.Slug, *parsed, stderr)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to verify commits: %v\n", err)
+			return 1
+		}
+		issues = append(issues, commitIssues...)
+		sort.SliceStable(issues, func(i, j int) bool { return issues[i].Line < issues[j].Line })
+	}
+	if len(issues) == 0 {
+		if *options.escalations {
+			fmt.Fprintf(stdout, "%s: no escalations\n", feature.Slug)
+			return 0
+		}
+		fmt.Fprintf(stdout, "%s: %s looks good\n", feature.Slug, session.Filename)
+		return 0
+	}
+
+	path := filepath.ToSlash(filepath.Join("changes", feature.Slug, session.Filename))
+	for _, issue := range issues {
+		if issue.Line == 0 {
+			fmt.Fprintf(stdout, "%s: %s\n", path, issue.Message)
+			continue
+		}
+		fmt.Fprintf(stdout, "%s:%d: %s\n", path, issue.Line, issue.Message)
+	}
+	fmt.Fprintf(stderr, "%s: %d session issue(s)\n", feature.Slug, len(issues))
+	return 1
+}
+
+// verifySessionCommits checks the Completed section's commit refs against git
+// history. Outside a git work tree the check is skipped with a note.
+func verifySessionCommits(ctx context.Context, projectRoot string, slug string, parsed session.Session, stderr io.Writer) ([]session.Issue, error) {
+	repo, err := openRepo(ctx, projectRoot)
+	if errors.Is(err, git.ErrNotRepository) {
+		fmt.Fprintf(stderr, "note: commit refs not verified: %v\n", err)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := tasks.Load(projectRoot, slug)
+	if err != nil {
+		return nil, err
+	}
+	return session.VerifyCommits(ctx, repo, parsed, list)
+}
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"opencode-spire/internal/git"
+)
+
+func TestRunSessionCheckReportsIssuesWithPaths(t *testing.T) {
+	projectRoot := createTaskFeature(t)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunSession(context.Background(), []string{"check", "003"}, projectRoot, &stdout, &stderr)
+
+	if exitCode != 1 {
+		t.Fatalf("exit code: got %d, want 1", exitCode)
+	}
+	for _, want := range []string{
+		"changes/003-retry/SESSION.md: missing section: ## Next Action",
+		"changes/003-retry/SESSION.md: missing Last updated line",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("stdout missing %q: %q", want, stdout.String())
+		}
+	}
+	if !strings.Contains(stderr.String(), "003-retry:") {
+		t.Fatalf("stderr: %q", stderr.String())
+	}
+}
+
+func TestRunSessionCheckPassesCompleteLog(t *testing.T) {
+	projectRoot := createTaskFeature(t)
+	updated := time.Now().Add(-time.Hour).Format("2006-01-02 15:04")
+	writeFile(t, filepath.Join(projectRoot, "changes", "003-retry", "SESSION.md"), "# Session Log: Retry\nLast updated: "+updated+" | Agent: sonnet\n\n## Status\nOverall: 30%\n\n## Completed (with commit refs)\n- T1 add backoff helper → commit 1111111\n\n## In Progress\n- T2\n\n## Closed Decisions\n\n## Discovered Constraints\n\n## Failure Log\n\n## Next Action\nStart T2.\n")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunSession(context.Background(), []string{"check", "retry", "--max-age", "24h"}, projectRoot, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stdout=%q stderr=%q", exitCode, stdout.String(), stderr.String())
+	}
+	if got := stdout.String(); got != "003-retry: SESSION.md looks good\n" {
+		t.Fatalf("stdout: %q", got)
+	}
+}
+
+func TestRunSessionCheckEscalationsFailsOnTrippedBreaker(t *testing.T) {
+	projectRoot := createTaskFeature(t)
+	sessionPath := filepath.Join(projectRoot, "changes", "003-retry", "SESSION.md")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if code := RunSession(context.Background(), []string{"check", "003", "--escalations"}, projectRoot, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code: got %d, stdout=%q", code, stdout.String())
+	}
+	if got := stdout.String(); got != "003-retry: no escalations\n" {
+		t.Fatalf("stdout: %q", got)
+	}
+
+	writeFile(t, sessionPath, "# Session Log\n\n## Failure Log\n- retry test: failed 3 times. Approach tried: backoff jitter.\n")
+	stdout.Reset()
+	if code := RunSession(context.Background(), []string{"check", "003", "--escalations"}, projectRoot, &stdout, &stderr); code != 1 {
+		t.Fatalf("exit code: got %d, want 1", code)
+	}
+	if want := "changes/003-retry/SESSION.md:4: escalation: retry test failed 3×"; !strings.Contains(stdout.String(), want) {
+		t.Fatalf("stdout missing %q: %q", want, stdout.String())
+	}
+}
+
+func TestRunSessionCheckVerifiesCommitRefs(t *testing.T) {
+	projectRoot := createTaskFeature(t)
+	original := openRepo
+	t.Cleanup(func() { openRepo = original })
+	openRepo = func(ctx context.Context, dir string) (git.Repo, error) {
+		return &git.Fake{Head: "2222222", Commits: map[string]git.FakeCommit{"2222222": {}}}, nil
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if code := RunSession(context.Background(), []string{"check", "003"}, projectRoot, &stdout, &stderr); code != 1 {
+		t.Fatalf("exit code: got %d, want 1", code)
+	}
+	if want := "changes/003-retry/SESSION.md:4: commit 1111111 does not exist"; !strings.Contains(stdout.String(), want) {
+		t.Fatalf("stdout missing %q: %q", want, stdout.String())
+	}
+}
@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"io"
 	"os"
@@ -8,12 +10,27 @@ import (
 	"regexp"
 	"sort"
 
+	"opencode-spire/internal/git"
 	projectstatus "opencode-spire/internal/status"
 )
 
 var statusSpecPattern = regexp.MustCompile(`^feature-(\d+)-(.+)\.md$`)
 
-func RunStatus(args []string, projectRoot string, stdout io.Writer, stderr io.Writer) int {
+func declareStatusFlags(flags *flag.FlagSet) *bool {
+	return flags.Bool("no-git", false, "only look at files, not branches and commit history")
+}
+
+func RunStatus(ctx context.Context, args []string, projectRoot string, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("status", stderr)
+	noGit := declareStatusFlags(flags)
+	if done, code := parseFlags(flags, args, stdout); done {
+		return code
+	}
+	if flags.NArg() > 0 {
+		printUsage(stderr, "status")
+		return 1
+	}
+
 	features, err := listFeatures(projectRoot)
 	if err != nil {
 		fmt.Fprintf(stderr, "failed to list features: %v\n", err)
@@ -25,6 +42,17 @@ func RunStatus(args []string, projectRoot string, stdout io.Writer, stderr io.Wr
 		return 0
 	}
 
+	var repo git.Repo
+	if !*noGit {
+		// Outside a git work tree, or in one without commits yet, the table
+		// simply has no git columns.
+		if opened, err := openRepo(ctx, projectRoot); err == nil {
+			if _, err := opened.ResolveCommit(ctx, "HEAD"); err == nil {
+				repo = opened
+			}
+		}
+	}
+
 	rows := make([]projectstatus.Row, 0, len(features))
 	for _, feature := range features {
 		state, err := projectstatus.Infer(projectRoot, feature.Slug)
@@ -33,11 +61,24 @@ func RunStatus(args []string, projectRoot string, stdout io.Writer, stderr io.Wr
 			return 1
 		}
 
-		rows = append(rows, projectstatus.Row{
+		row := projectstatus.Row{
 			Number:  feature.Number,
 			Feature: feature.Name,
 			Status:  state,
-		})
+		}
+		if repo != nil {
+			gitState, err := projectstatus.InspectGit(ctx, repo, feature.Slug)
+			if err != nil {
+				// Git data is a refinement; without it the row keeps its
+				// file-based state.
+				fmt.Fprintf(stderr, "warning: no git history for %s: %v\n", feature.Slug, err)
+				gitState = projectstatus.GitState{}
+			}
+			row.Status = projectstatus.WithGit(state, gitState)
+			row.Branch = gitState.BranchSummary()
+			row.LastActivity = gitState.LastActivitySummary()
+		}
+		rows = append(rows, row)
 	}
 
 	fmt.Fprint(stdout, projectstatus.RenderTable(rows))
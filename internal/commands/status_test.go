@@ -2,10 +2,16 @@ package commands
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"opencode-spire/internal/git"
 )
 
 func TestRunStatusEmptyProject(t *testing.T) {
@@ -13,7 +19,7 @@ func TestRunStatusEmptyProject(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunStatus(nil, projectRoot, &stdout, &stderr)
+	exitCode := RunStatus(context.Background(), nil, projectRoot, &stdout, &stderr)
 
 	if exitCode != 0 {
 		t.Fatalf("exit code: got %d, want 0", exitCode)
@@ -32,7 +38,7 @@ func TestRunStatusTableOutput(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunStatus(nil, projectRoot, &stdout, &stderr)
+	exitCode := RunStatus(context.Background(), nil, projectRoot, &stdout, &stderr)
 
 	if exitCode != 0 {
 		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
@@ -50,6 +56,90 @@ func TestRunStatusTableOutput(t *testing.T) {
 	}
 }
 
+func TestRunStatusAddsGitColumns(t *testing.T) {
+	projectRoot := t.TempDir()
+	writeStatusFixture(t, filepath.Join(projectRoot, "specs", "feature-001-alpha.md"), "x")
+	writeStatusFixture(t, filepath.Join(projectRoot, "changes", "001-alpha", "VERIFICATION_REPORT.md"), "READY FOR PR\n")
+
+	original := openRepo
+	t.Cleanup(func() { openRepo = original })
+	openRepo = func(ctx context.Context, dir string) (git.Repo, error) {
+		when := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+		return &git.Fake{
+			Head: "m2",
+			Refs: map[string]string{"main": "m2", "001-alpha": "a1"},
+			Commits: map[string]git.FakeCommit{
+				"m1": {},
+				"a1": {Parents: []string{"m1"}, Files: []string{"changes/001-alpha/VERIFICATION_REPORT.md"}, Time: when},
+				"m2": {Parents: []string{"m1", "a1"}, Time: when},
+			},
+		}, nil
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if code := RunStatus(context.Background(), nil, projectRoot, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", code, stderr.String())
+	}
+	for _, want := range []string{"Last activity", "001-alpha (merged)", "2026-03-10", "Merged, not archived"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("stdout missing %q: %q", want, stdout.String())
+		}
+	}
+
+	stdout.Reset()
+	if code := RunStatus(context.Background(), []string{"--no-git"}, projectRoot, &stdout, &stderr); code != 0 {
+		t.Fatalf("--no-git exit code: got %d", code)
+	}
+	if strings.Contains(stdout.String(), "Branch") || !strings.Contains(stdout.String(), "Awaiting PR") {
+		t.Fatalf("--no-git stdout: %q", stdout.String())
+	}
+}
+
+type brokenHistory struct {
+	*git.Fake
+}
+
+func (brokenHistory) LastCommitTime(ctx context.Context, path string) (time.Time, error) {
+	return time.Time{}, errors.New("git log: exit status 128")
+}
+
+func TestRunStatusWithoutGitHistory(t *testing.T) {
+	projectRoot := t.TempDir()
+	writeStatusFixture(t, filepath.Join(projectRoot, "specs", "feature-001-alpha.md"), "x")
+
+	original := openRepo
+	t.Cleanup(func() { openRepo = original })
+	openRepo = func(ctx context.Context, dir string) (git.Repo, error) {
+		return brokenHistory{&git.Fake{Head: "m1", Refs: map[string]string{"main": "m1"}, Commits: map[string]git.FakeCommit{"m1": {}}}}, nil
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if code := RunStatus(context.Background(), nil, projectRoot, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Spec only") || !strings.Contains(stderr.String(), "warning: no git history for 001-alpha") {
+		t.Fatalf("stdout=%q stderr=%q", stdout.String(), stderr.String())
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	openRepo = original
+	if out, err := exec.Command("git", "init", "-q", projectRoot).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	stdout.Reset()
+	stderr.Reset()
+	if code := RunStatus(context.Background(), nil, projectRoot, &stdout, &stderr); code != 0 {
+		t.Fatalf("repo without commits: exit code %d, stderr=%q", code, stderr.String())
+	}
+	if strings.Contains(stdout.String(), "Branch") || !strings.Contains(stdout.String(), "Spec only") {
+		t.Fatalf("repo without commits stdout: %q", stdout.String())
+	}
+}
+
 func writeStatusFixture(t *testing.T, path string, content string) {
 	t.Helper()
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
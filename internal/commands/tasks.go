@@ -0,0 +1,213 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+
+	"opencode-spire/internal/session"
+	"opencode-spire/internal/tasks"
+)
+
+var commitRefPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+func declareTasksFlags(flags *flag.FlagSet) *string {
+	return flags.String("commit", "", "with done, the `sha` recorded in SESSION.md's Completed section")
+}
+
+func RunTasks(ctx context.Context, args []string, projectRoot string, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("tasks", stderr)
+	commit := declareTasksFlags(flags)
+	if done, code := parseFlags(flags, args, stdout); done {
+		return code
+	}
+
+	positional := flags.Args()
+	switch {
+	case len(positional) == 3 && positional[0] == "done":
+		return runTasksDone(projectRoot, positional[1], positional[2], strings.TrimSpace(*commit), stdout, stderr)
+	case len(positional) <= 2 && len(positional) > 0 && positional[0] == "next":
+		return runTasksNext(projectRoot, positional[1:], stdout, stderr)
+	case len(positional) == 1 && *commit == "":
+		return runTasksList(projectRoot, positional[0], stdout, stderr)
+	default:
+		printUsage(stderr, "tasks")
+		return 1
+	}
+}
+
+func runTasksList(projectRoot string, ref string, stdout io.Writer, stderr io.Writer) int {
+	feature, list, ok := loadFeatureTasks(projectRoot, ref, stderr)
+	if !ok {
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "%s: %s\n", feature.Slug, list.Progress())
+	writer := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	for _, task := range list.Tasks {
+		box := "[ ]"
+		if task.Done {
+			box = "[x]"
+		}
+		note := ""
+		if blockers := list.Blockers(task); !task.Done && len(blockers) > 0 {
+			note = fmt.Sprintf(" (blocked by %s)", strings.Join(blockers, ", "))
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s%s\n", box, task.ID, task.Title, note)
+	}
+	writer.Flush()
+	return 0
+}
+
+func runTasksDone(projectRoot string, ref string, id string, commit string, stdout io.Writer, stderr io.Writer) int {
+	if commit != "" && !commitRefPattern.MatchString(commit) {
+		fmt.Fprintf(stderr, "invalid commit %q (want 7-40 lowercase hex characters)\n", commit)
+		return 1
+	}
+
+	feature, list, ok := loadFeatureTasks(projectRoot, ref, stderr)
+	if !ok {
+		return 1
+	}
+	task, found := list.Find(id)
+	if !found {
+		fmt.Fprintf(stderr, "failed to check off task: unknown task %s\n", strings.ToUpper(strings.TrimSpace(id)))
+		return 1
+	}
+	if blockers := list.Blockers(task); !task.Done && len(blockers) > 0 {
+		fmt.Fprintf(stderr, "warning: %s was blocked by %s\n", task.ID, strings.Join(blockers, ", "))
+	}
+
+	// Record the commit before ticking the box: a failed write then leaves the
+	// task open, and a retry records it again (AppendCompleted skips repeats).
+	recorded := false
+	if commit != "" {
+		sessionPath := session.Path(projectRoot, feature.Slug)
+		if _, err := os.Stat(sessionPath); err != nil {
+			fmt.Fprintf(stderr, "warning: commit not recorded: %v\n", err)
+		} else if err := session.AppendCompleted(sessionPath, fmt.Sprintf("%s %s → commit %s", task.ID, task.Title, commit)); err != nil {
+			fmt.Fprintf(stderr, "failed to record commit in SESSION.md: %v\n", err)
+			return 1
+		} else {
+			recorded = true
+		}
+	}
+
+	task, already, err := tasks.MarkDone(tasks.Path(projectRoot, feature.Slug), task.ID)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to check off task: %v\n", err)
+		return 1
+	}
+	if already {
+		fmt.Fprintf(stdout, "%s is already done\n", task.ID)
+	} else {
+		fmt.Fprintf(stdout, "checked: %s %s\n", task.ID, task.Title)
+	}
+	if recorded {
+		fmt.Fprintf(stdout, "recorded: %s → commit %s\n", task.ID, commit)
+	}
+	return 0
+}
+
+func runTasksNext(projectRoot string, refs []string, stdout io.Writer, stderr io.Writer) int {
+	var feature featureEntry
+	var list *tasks.List
+	if len(refs) == 1 {
+		var ok bool
+		if feature, list, ok = loadFeatureTasks(projectRoot, refs[0], stderr); !ok {
+			return 1
+		}
+	} else {
+		var err error
+		if feature, list, err = soleOpenTaskList(projectRoot); err != nil {
+			fmt.Fprintf(stderr, "%v\n", err)
+			return 1
+		}
+	}
+
+	progress := list.Progress()
+	if progress.Next == nil {
+		if progress.Done == progress.Total {
+			fmt.Fprintf(stdout, "%s: all %d tasks done\n", feature.Slug, progress.Total)
+			return 0
+		}
+		fmt.Fprintf(stderr, "%s: %s\n", feature.Slug, progress)
+		return 1
+	}
+
+	next := progress.Next
+	fmt.Fprintf(stdout, "%s: %s %s\n", feature.Slug, next.ID, next.Title)
+	printTaskField(stdout, "Goal", next.Goal)
+	printTaskField(stdout, "Files", strings.Join(next.Files, ", "))
+	printTaskField(stdout, "Tests", next.Tests)
+	printTaskField(stdout, "Verification", next.Verification)
+	return 0
+}
+
+func printTaskField(w io.Writer, name string, value string) {
+	if value != "" {
+		fmt.Fprintf(w, "  %s: %s\n", name, value)
+	}
+}
+
+func loadFeatureTasks(projectRoot string, ref string, stderr io.Writer) (featureEntry, *tasks.List, bool) {
+	feature, err := resolveFeature(projectRoot, ref)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return featureEntry{}, nil, false
+	}
+
+	list, err := tasks.Load(projectRoot, feature.Slug)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to read tasks: %v\n", err)
+		return featureEntry{}, nil, false
+	}
+	if list == nil {
+		fmt.Fprintf(stderr, "no %s for %s\n", tasks.Filename, feature.Slug)
+		return featureEntry{}, nil, false
+	}
+	return feature, list, true
+}
+
+// soleOpenTaskList picks the only feature whose TASKS.md still has unchecked
+// tasks, so `spire tasks next` works without naming the feature.
+func soleOpenTaskList(projectRoot string) (featureEntry, *tasks.List, error) {
+	features, err := listFeatures(projectRoot)
+	if err != nil {
+		return featureEntry{}, nil, fmt.Errorf("failed to list features: %w", err)
+	}
+
+	var open []featureEntry
+	var lists []*tasks.List
+	for _, feature := range features {
+		list, err := tasks.Load(projectRoot, feature.Slug)
+		if err != nil {
+			return featureEntry{}, nil, fmt.Errorf("failed to read tasks: %w", err)
+		}
+		if list == nil {
+			continue
+		}
+		if progress := list.Progress(); progress.Done < progress.Total {
+			open = append(open, feature)
+			lists = append(lists, list)
+		}
+	}
+
+	switch len(open) {
+	case 0:
+		return featureEntry{}, nil, fmt.Errorf("no feature has open tasks")
+	case 1:
+		return open[0], lists[0], nil
+	default:
+		slugs := make([]string, 0, len(open))
+		for _, feature := range open {
+			slugs = append(slugs, feature.Slug)
+		}
+		return featureEntry{}, nil, fmt.Errorf("several features have open tasks (%s); use spire tasks next <feature>", strings.Join(slugs, ", "))
+	}
+}
@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const featureTasks = "# Tasks\n\n- [x] T1: add backoff helper\n- [ ] T2: retry on 5xx\n  - Goal: retry transient failures\n  - Depends on: T1\n- [ ] T3: resume downloads\n  - Depends on: T2\n"
+
+func createTaskFeature(t *testing.T) string {
+	t.Helper()
+
+	projectRoot := t.TempDir()
+	writeFile(t, filepath.Join(projectRoot, "specs", "feature-003-retry.md"), "# Spec\n")
+	writeFile(t, filepath.Join(projectRoot, "changes", "003-retry", "TASKS.md"), featureTasks)
+	writeFile(t, filepath.Join(projectRoot, "changes", "003-retry", "SESSION.md"), "# Session Log\n\n## Completed (with commit refs)\n- T1 add backoff helper → commit 1111111\n\n## In Progress\n- T2\n")
+	return projectRoot
+}
+
+func TestRunTasksListsStateAndBlockers(t *testing.T) {
+	projectRoot := createTaskFeature(t)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunTasks(context.Background(), []string{"003"}, projectRoot, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	for _, want := range []string{"003-retry: 1/3 tasks, next: T2 retry on 5xx", "[x]  T1  add backoff helper", "T3  resume downloads (blocked by T2)"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("stdout missing %q: %q", want, stdout.String())
+		}
+	}
+}
+
+func TestRunTasksDoneTicksBoxAndRecordsCommit(t *testing.T) {
+	projectRoot := createTaskFeature(t)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunTasks(context.Background(), []string{"done", "retry", "T2", "--commit", "abc1234"}, projectRoot, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	assertFileContains(t, filepath.Join(projectRoot, "changes", "003-retry", "TASKS.md"), "- [x] T2: retry on 5xx")
+
+	session := string(mustReadFile(t, filepath.Join(projectRoot, "changes", "003-retry", "SESSION.md")))
+	want := "- T1 add backoff helper → commit 1111111\n- T2 retry on 5xx → commit abc1234\n\n## In Progress"
+	if !strings.Contains(session, want) {
+		t.Fatalf("SESSION.md: %q", session)
+	}
+
+	stdout.Reset()
+	if code := RunTasks(context.Background(), []string{"next", "003"}, projectRoot, &stdout, &stderr); code != 0 {
+		t.Fatalf("next failed with code %d", code)
+	}
+	if !strings.HasPrefix(stdout.String(), "003-retry: T3 resume downloads") {
+		t.Fatalf("next: %q", stdout.String())
+	}
+}
+
+func TestRunTasksDoneRecordsCommitForFinishedTask(t *testing.T) {
+	projectRoot := createTaskFeature(t)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	for range 2 {
+		stdout.Reset()
+		exitCode := RunTasks(context.Background(), []string{"done", "003", "T1", "--commit", "2222222"}, projectRoot, &stdout, &stderr)
+		if exitCode != 0 {
+			t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+		}
+	}
+
+	if !strings.Contains(stdout.String(), "T1 is already done") || !strings.Contains(stdout.String(), "recorded: T1 → commit 2222222") {
+		t.Fatalf("stdout: %q", stdout.String())
+	}
+	session := string(mustReadFile(t, filepath.Join(projectRoot, "changes", "003-retry", "SESSION.md")))
+	if got := strings.Count(session, "commit 2222222"); got != 1 {
+		t.Fatalf("commit recorded %d times: %q", got, session)
+	}
+}
+
+func TestRunTasksDoneLeavesBoxWhenSessionWriteFails(t *testing.T) {
+	projectRoot := createTaskFeature(t)
+	sessionPath := filepath.Join(projectRoot, "changes", "003-retry", "SESSION.md")
+	if err := os.Remove(sessionPath); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := os.Mkdir(sessionPath, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	var stderr bytes.Buffer
+	if code := RunTasks(context.Background(), []string{"done", "003", "T2", "--commit", "abc1234"}, projectRoot, &bytes.Buffer{}, &stderr); code != 1 {
+		t.Fatalf("exit code: got %d, want 1", code)
+	}
+	assertFileContains(t, filepath.Join(projectRoot, "changes", "003-retry", "TASKS.md"), "- [ ] T2: retry on 5xx")
+}
+
+func TestRunTasksDoneRejectsBadCommitAndUnknownTask(t *testing.T) {
+	projectRoot := createTaskFeature(t)
+
+	var stderr bytes.Buffer
+	if code := RunTasks(context.Background(), []string{"done", "003", "T2", "--commit", "HEAD"}, projectRoot, &bytes.Buffer{}, &stderr); code != 1 {
+		t.Fatalf("bad commit: exit code %d", code)
+	}
+	if !strings.Contains(stderr.String(), `invalid commit "HEAD"`) {
+		t.Fatalf("stderr: %q", stderr.String())
+	}
+
+	stderr.Reset()
+	if code := RunTasks(context.Background(), []string{"done", "003", "T9"}, projectRoot, &bytes.Buffer{}, &stderr); code != 1 {
+		t.Fatalf("unknown task: exit code %d", code)
+	}
+	if !strings.Contains(stderr.String(), "unknown task T9") {
+		t.Fatalf("stderr: %q", stderr.String())
+	}
+}
+
+func TestRunTasksNextPicksTheOnlyOpenFeature(t *testing.T) {
+	projectRoot := createTaskFeature(t)
+	writeFile(t, filepath.Join(projectRoot, "specs", "feature-004-done.md"), "# Spec\n")
+	writeFile(t, filepath.Join(projectRoot, "changes", "004-done", "TASKS.md"), "- [x] T1: finished\n")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunTasks(context.Background(), []string{"next"}, projectRoot, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "003-retry: T2 retry on 5xx\n  Goal: retry transient failures\n") {
+		t.Fatalf("stdout: %q", stdout.String())
+	}
+}
@@ -2,17 +2,38 @@ package commands
 
 import (
 	"bufio"
+	"bytes"
+	"context"
+	"flag"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"opencode-spire/internal/download"
 	"opencode-spire/internal/methodology"
 	"opencode-spire/internal/scaffold"
 )
 
-func RunUpdate(args []string, projectRoot string, stdin io.Reader, interactive bool, stdout io.Writer, stderr io.Writer) int {
+func declareUpdateFlags(flags *flag.FlagSet) (*bool, *time.Duration) {
+	offline := flags.Bool("offline", false, "use the newest cached methodology payload instead of downloading")
+	timeout := flags.Duration("timeout", download.DefaultTimeout, "timeout for each download attempt")
+	return offline, timeout
+}
+
+func RunUpdate(ctx context.Context, args []string, projectRoot string, stdin io.Reader, interactive bool, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("update", stderr)
+	offline, timeout := declareUpdateFlags(flags)
+	if done, code := parseFlags(flags, args, stdout); done {
+		return code
+	}
+	if flags.NArg() > 0 {
+		printUsage(stderr, "update")
+		return 1
+	}
+
 	methodologyPath := filepath.Join(projectRoot, ".methodology")
 	info, err := os.Stat(methodologyPath)
 	if err != nil {
@@ -61,13 +82,42 @@ func RunUpdate(args []string, projectRoot string, stdin io.Reader, interactive b
 	if metadata != nil {
 		source = *metadata
 	}
+	if source.Embedded {
+		fmt.Fprintf(stdout, "moving from embedded payload (version %s) to %s@%s\n", source.Version, source.Repository, source.Ref)
+		source = source.OnlineSource()
+	}
+
+	upstream, err := scaffold.UpstreamHashes(projectRoot, methodologyPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "warning: cannot read current project root mappings; edited files will be kept: %v\n", err)
+	}
+
+	// The payload and the root projection are committed together: if the
+	// projection fails, .methodology goes back to the checkpoint.
+	checkpoint, err := methodology.NewCheckpoint(ctx, methodologyPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to save current methodology payload: %v\n", err)
+		return 1
+	}
+	defer checkpoint.Discard()
 
-	changedFiles, _, err := methodology.SyncAndReportChangesFromMetadata(methodologyPath, source)
+	changedFiles, _, err := methodology.SyncAndReportChangesFromMetadata(ctx, methodologyPath, source, fetchOptions(*offline, *timeout, stderr))
 	if err != nil {
 		fmt.Fprintf(stderr, "failed to update methodology payload: %v\n", err)
 		return 1
 	}
 
+	var projected bytes.Buffer
+	if err := scaffold.ApplyProjectRootUpdateMappings(ctx, projectRoot, methodologyPath, changedFiles, upstream, &projected); err != nil {
+		fmt.Fprintf(stderr, "failed to apply project root mappings: %v\n", err)
+		if restoreErr := checkpoint.Restore(); restoreErr != nil {
+			fmt.Fprintf(stderr, "failed to restore previous methodology payload: %v\n", restoreErr)
+		} else {
+			fmt.Fprintln(stderr, "restored previous .methodology; nothing was updated")
+		}
+		return 1
+	}
+
 	fmt.Fprintln(stdout, "updated .methodology")
 	if len(changedFiles) == 0 {
 		fmt.Fprintln(stdout, "no methodology file changes detected")
@@ -77,11 +127,9 @@ func RunUpdate(args []string, projectRoot string, stdin io.Reader, interactive b
 			fmt.Fprintf(stdout, "- %s\n", file)
 		}
 	}
+	_, _ = projected.WriteTo(stdout)
 
-	if err := scaffold.ApplyProjectRootUpdateMappings(projectRoot, methodologyPath, changedFiles, stdout); err != nil {
-		fmt.Fprintf(stderr, "failed to apply project root mappings: %v\n", err)
-		return 1
-	}
+	warnOpencodeIssues(projectRoot, stderr)
 
 	return 0
 }
@@ -2,6 +2,8 @@ package commands
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -17,7 +19,7 @@ func TestRunUpdateWithoutMethodologyAborts(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunUpdate(nil, projectRoot, strings.NewReader(""), true, &stdout, &stderr)
+	exitCode := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), true, &stdout, &stderr)
 
 	if exitCode != 1 {
 		t.Fatalf("exit code: got %d, want 1", exitCode)
@@ -32,7 +34,7 @@ func TestRunUpdateCleanReportsChangedFiles(t *testing.T) {
 	source := createMethodologySource(t)
 	configureCanonicalSourceFromDir(t, source)
 
-	if code := RunInit(nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
 		t.Fatalf("init failed with code %d", code)
 	}
 
@@ -40,7 +42,7 @@ func TestRunUpdateCleanReportsChangedFiles(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunUpdate(nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
+	exitCode := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
 
 	if exitCode != 0 {
 		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
@@ -59,7 +61,7 @@ func TestRunUpdateDirtyPromptsAndAbortsOnNo(t *testing.T) {
 	source := createMethodologySource(t)
 	configureCanonicalSourceFromDir(t, source)
 
-	if code := RunInit(nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
 		t.Fatalf("init failed with code %d", code)
 	}
 
@@ -67,7 +69,7 @@ func TestRunUpdateDirtyPromptsAndAbortsOnNo(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunUpdate(nil, projectRoot, strings.NewReader("n\n"), true, &stdout, &stderr)
+	exitCode := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader("n\n"), true, &stdout, &stderr)
 
 	if exitCode != 1 {
 		t.Fatalf("exit code: got %d, want 1", exitCode)
@@ -85,7 +87,7 @@ func TestRunUpdateDirtyContinuesOnYes(t *testing.T) {
 	source := createMethodologySource(t)
 	configureCanonicalSourceFromDir(t, source)
 
-	if code := RunInit(nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
 		t.Fatalf("init failed with code %d", code)
 	}
 
@@ -93,7 +95,7 @@ func TestRunUpdateDirtyContinuesOnYes(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunUpdate(nil, projectRoot, strings.NewReader("y\n"), true, &stdout, &stderr)
+	exitCode := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader("y\n"), true, &stdout, &stderr)
 
 	if exitCode != 0 {
 		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
@@ -108,7 +110,7 @@ func TestRunUpdateDirtyNonInteractiveAborts(t *testing.T) {
 	source := createMethodologySource(t)
 	configureCanonicalSourceFromDir(t, source)
 
-	if code := RunInit(nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
 		t.Fatalf("init failed with code %d", code)
 	}
 
@@ -116,7 +118,7 @@ func TestRunUpdateDirtyNonInteractiveAborts(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunUpdate(nil, projectRoot, strings.NewReader("y\n"), false, &stdout, &stderr)
+	exitCode := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader("y\n"), false, &stdout, &stderr)
 
 	if exitCode != 1 {
 		t.Fatalf("exit code: got %d, want 1", exitCode)
@@ -131,7 +133,7 @@ func TestRunUpdateRootMappingNoticeWithoutOverwrite(t *testing.T) {
 	source := createMethodologySource(t)
 	configureCanonicalSourceFromDir(t, source)
 
-	if code := RunInit(nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
 		t.Fatalf("init failed with code %d", code)
 	}
 
@@ -143,7 +145,7 @@ func TestRunUpdateRootMappingNoticeWithoutOverwrite(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunUpdate(nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
+	exitCode := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
 
 	if exitCode != 0 {
 		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
@@ -167,7 +169,7 @@ func TestRunUpdateOpencodeMappingOverwritesWhenSourceChanged(t *testing.T) {
 	source := createMethodologySource(t)
 	configureCanonicalSourceFromDir(t, source)
 
-	if code := RunInit(nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
 		t.Fatalf("init failed with code %d", code)
 	}
 
@@ -184,7 +186,7 @@ func TestRunUpdateOpencodeMappingOverwritesWhenSourceChanged(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunUpdate(nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
+	exitCode := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
 
 	if exitCode != 0 {
 		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
@@ -208,7 +210,7 @@ func TestRunUpdateUsesStoredMetadataOverCurrentDefaults(t *testing.T) {
 	source := createMethodologySource(t)
 	configureCanonicalSourceFromDir(t, source)
 
-	if code := RunInit(nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
 		t.Fatalf("init failed with code %d", code)
 	}
 
@@ -219,7 +221,7 @@ func TestRunUpdateUsesStoredMetadataOverCurrentDefaults(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunUpdate(nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
+	exitCode := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
 
 	if exitCode != 0 {
 		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
@@ -234,7 +236,7 @@ func TestRunUpdateFallsBackWhenMetadataMissing(t *testing.T) {
 	source := createMethodologySource(t)
 	configureCanonicalSourceFromDir(t, source)
 
-	if code := RunInit(nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
 		t.Fatalf("init failed with code %d", code)
 	}
 
@@ -246,7 +248,7 @@ func TestRunUpdateFallsBackWhenMetadataMissing(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunUpdate(nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
+	exitCode := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
 
 	if exitCode != 0 {
 		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
@@ -255,3 +257,490 @@ func TestRunUpdateFallsBackWhenMetadataMissing(t *testing.T) {
 		t.Fatalf("stdout: %q", stdout.String())
 	}
 }
+
+func TestRunUpdateJSONMergeAddsUpstreamInstructionsOnce(t *testing.T) {
+	projectRoot := t.TempDir()
+	source := createMethodologySource(t)
+	setManifestPolicy(t, source, "opencode.json", "if_missing", "json_merge")
+	configureCanonicalSourceFromDir(t, source)
+
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+
+	localOpenCode := "{\n  \"model\": \"local/model\",\n  \"instructions\": [\n    \"AGENTS.md\",\n    \"docs/team.md\"\n  ]\n}\n"
+	writeFile(t, filepath.Join(projectRoot, "opencode.json"), localOpenCode)
+	writeFile(t, filepath.Join(source, "project_root", "opencode.json"), "{\n  \"model\": \"upstream/model\",\n  \"instructions\": [\n    \".methodology/agents/SPIRE.md\",\n    \"AGENTS.md\"\n  ]\n}\n")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "merged: opencode.json (instructions)") {
+		t.Fatalf("missing merge report: %q", stdout.String())
+	}
+
+	want := "{\n  \"model\": \"local/model\",\n  \"instructions\": [\n    \"AGENTS.md\",\n    \"docs/team.md\",\n    \".methodology/agents/SPIRE.md\"\n  ]\n}\n"
+	if got := string(mustReadFile(t, filepath.Join(projectRoot, "opencode.json"))); got != want {
+		t.Fatalf("opencode.json: got %q, want %q", got, want)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("second update exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if strings.Contains(stdout.String(), "merged: opencode.json") {
+		t.Fatalf("second update merged again: %q", stdout.String())
+	}
+	if got := string(mustReadFile(t, filepath.Join(projectRoot, "opencode.json"))); got != want {
+		t.Fatalf("opencode.json changed on second update: %q", got)
+	}
+}
+
+func TestRunUpdateJSONMergeKeepsInstructionsRemovedLocally(t *testing.T) {
+	projectRoot := t.TempDir()
+	source := createMethodologySource(t)
+	setManifestPolicy(t, source, "opencode.json", "json_merge", "json_merge")
+	configureCanonicalSourceFromDir(t, source)
+
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+
+	writeFile(t, filepath.Join(projectRoot, "opencode.json"), "{\n  \"instructions\": [\n    \"AGENTS.md\"\n  ]\n}\n")
+	writeFile(t, filepath.Join(source, "project_root", "opencode.json"), "{\n  \"instructions\": [\n    \".methodology/agents/SPIRE.md\",\n    \"AGENTS.md\",\n    \"specs/PRODUCT.md\"\n  ]\n}\n")
+
+	want := "{\n  \"instructions\": [\n    \"AGENTS.md\",\n    \"specs/PRODUCT.md\"\n  ]\n}\n"
+	for i := 0; i < 2; i++ {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		exitCode := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
+
+		if exitCode != 0 {
+			t.Fatalf("update %d exit code: got %d, stderr=%q", i+1, exitCode, stderr.String())
+		}
+		if got := string(mustReadFile(t, filepath.Join(projectRoot, "opencode.json"))); got != want {
+			t.Fatalf("opencode.json after update %d: got %q, want %q", i+1, got, want)
+		}
+	}
+}
+
+func setManifestPolicy(t *testing.T, sourceDir string, destination string, onInit string, onUpdate string) {
+	t.Helper()
+
+	manifestPath := filepath.Join(sourceDir, "project_root", "manifest.json")
+	var manifest map[string]any
+	if err := json.Unmarshal(mustReadFile(t, manifestPath), &manifest); err != nil {
+		t.Fatalf("parse manifest: %v", err)
+	}
+
+	for _, raw := range manifest["mappings"].([]any) {
+		mapping := raw.(map[string]any)
+		if mapping["destination"] == destination {
+			mapping["on_init"] = onInit
+			mapping["on_update"] = onUpdate
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		t.Fatalf("serialize manifest: %v", err)
+	}
+	writeFile(t, manifestPath, string(data))
+}
+
+func TestRunUpdateManagedBlockRefreshesOnlyMarkedRegion(t *testing.T) {
+	projectRoot := t.TempDir()
+	source := createMethodologySource(t)
+	setManifestPolicy(t, source, "AGENTS.md", "managed_block", "managed_block")
+	writeFile(t, filepath.Join(source, "project_root", "local_agents.md"), "# Project\n\n<!-- spire:begin -->\nrules v1\n<!-- spire:end -->\n")
+	configureCanonicalSourceFromDir(t, source)
+
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+
+	agentsPath := filepath.Join(projectRoot, "AGENTS.md")
+	writeFile(t, agentsPath, "# My Project\n\n<!-- spire:begin -->\nrules v1\n<!-- spire:end -->\n\n## Local Rules\nkeep me\n")
+	writeFile(t, filepath.Join(source, "project_root", "local_agents.md"), "# Project\n\n<!-- spire:begin -->\nrules v2\n<!-- spire:end -->\n")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "updated block: AGENTS.md") {
+		t.Fatalf("missing block update notice: %q", stdout.String())
+	}
+
+	want := "# My Project\n\n<!-- spire:begin -->\nrules v2\n<!-- spire:end -->\n\n## Local Rules\nkeep me\n"
+	if got := string(mustReadFile(t, agentsPath)); got != want {
+		t.Fatalf("AGENTS.md: got %q, want %q", got, want)
+	}
+}
+
+func TestRunUpdateOverwriteIfUnmodifiedRefreshesOnlyUntouchedFiles(t *testing.T) {
+	projectRoot := t.TempDir()
+	source := createMethodologySource(t)
+	setManifestPolicy(t, source, ".opencode/agents/verifier.md", "overwrite_if_unmodified", "overwrite_if_unmodified")
+	setManifestPolicy(t, source, ".opencode/agents/investigator.md", "overwrite_if_unmodified", "overwrite_if_unmodified")
+	configureCanonicalSourceFromDir(t, source)
+
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+
+	investigatorPath := filepath.Join(projectRoot, ".opencode", "agents", "investigator.md")
+	writeFile(t, investigatorPath, "local custom\n")
+
+	writeFile(t, filepath.Join(source, "subagents", "verifier.md"), "---\nmode: subagent\n---\nverifier v2\n")
+	writeFile(t, filepath.Join(source, "subagents", "investigator.md"), "---\nmode: subagent\n---\ninvestigator v2\n")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "updated: .opencode/agents/verifier.md") {
+		t.Fatalf("missing verifier update: %q", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "notice: .opencode/agents/investigator.md was modified locally; kept existing") {
+		t.Fatalf("missing investigator notice: %q", stdout.String())
+	}
+
+	assertFileContains(t, filepath.Join(projectRoot, ".opencode", "agents", "verifier.md"), "verifier v2")
+	if got := string(mustReadFile(t, investigatorPath)); got != "local custom\n" {
+		t.Fatalf("investigator.md overwritten: %q", got)
+	}
+}
+
+func TestRunUpdateOverwriteIfUnmodifiedWithoutLedgerUsesPreviousPayload(t *testing.T) {
+	projectRoot := t.TempDir()
+	source := createMethodologySource(t)
+	configureCanonicalSourceFromDir(t, source)
+
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+
+	// An install from before the projection ledger has only payload hashes.
+	statePath := filepath.Join(projectRoot, ".methodology", ".spire-sync-state.json")
+	var state map[string]any
+	if err := json.Unmarshal(mustReadFile(t, statePath), &state); err != nil {
+		t.Fatalf("parse sync state: %v", err)
+	}
+	delete(state, "projections")
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatalf("serialize sync state: %v", err)
+	}
+	writeFile(t, statePath, string(data))
+
+	investigatorPath := filepath.Join(projectRoot, ".opencode", "agents", "investigator.md")
+	writeFile(t, investigatorPath, "local custom\n")
+
+	setManifestPolicy(t, source, ".opencode/agents/verifier.md", "overwrite_if_unmodified", "overwrite_if_unmodified")
+	setManifestPolicy(t, source, ".opencode/agents/investigator.md", "overwrite_if_unmodified", "overwrite_if_unmodified")
+	writeFile(t, filepath.Join(source, "subagents", "verifier.md"), "---\nmode: subagent\n---\nverifier v2\n")
+	writeFile(t, filepath.Join(source, "subagents", "investigator.md"), "---\nmode: subagent\n---\ninvestigator v2\n")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "updated: .opencode/agents/verifier.md") {
+		t.Fatalf("missing verifier update: %q", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "notice: .opencode/agents/investigator.md was modified locally; kept existing") {
+		t.Fatalf("missing investigator notice: %q", stdout.String())
+	}
+	assertFileContains(t, filepath.Join(projectRoot, ".opencode", "agents", "verifier.md"), "verifier v2")
+	if got := string(mustReadFile(t, investigatorPath)); got != "local custom\n" {
+		t.Fatalf("investigator.md overwritten: %q", got)
+	}
+}
+
+func TestRunUpdateVersion1ManifestKeepsEditedOpencodeAgent(t *testing.T) {
+	projectRoot := t.TempDir()
+	source := createMethodologySource(t)
+	setManifestPolicy(t, source, ".opencode/agents/verifier.md", "if_missing", "never_overwrite")
+
+	manifestPath := filepath.Join(source, "project_root", "manifest.json")
+	var manifest map[string]any
+	if err := json.Unmarshal(mustReadFile(t, manifestPath), &manifest); err != nil {
+		t.Fatalf("parse manifest: %v", err)
+	}
+	manifest["version"] = 1
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		t.Fatalf("serialize manifest: %v", err)
+	}
+	writeFile(t, manifestPath, string(data))
+	configureCanonicalSourceFromDir(t, source)
+
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+
+	verifierPath := filepath.Join(projectRoot, ".opencode", "agents", "verifier.md")
+	writeFile(t, verifierPath, "---\nmode: subagent\n---\nlocal verifier rules\n")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if got := string(mustReadFile(t, verifierPath)); got != "---\nmode: subagent\n---\nlocal verifier rules\n" {
+		t.Fatalf("verifier.md overwritten: %q", got)
+	}
+}
+
+func TestRunUpdateWarnsWhenPreviousPayloadCannotBeProjected(t *testing.T) {
+	projectRoot := t.TempDir()
+	source := createMethodologySource(t)
+	writeFile(t, filepath.Join(source, "project_root", "notes.md"), "{{ .Missing }}\n")
+	addManifestMapping(t, source, map[string]any{
+		"source":      "project_root/notes.md",
+		"destination": "NOTES.md",
+		"on_init":     "if_missing",
+		"on_update":   "render",
+	})
+	configureCanonicalSourceFromDir(t, source)
+
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+
+	writeFile(t, filepath.Join(source, "project_root", "notes.md"), "# {{ .ProjectName }}\n")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "warning: cannot read current project root mappings; edited files will be kept: render template") {
+		t.Fatalf("missing warning: %q", stderr.String())
+	}
+}
+
+func TestRunUpdateRollsBackRootFilesWhenProjectionFails(t *testing.T) {
+	projectRoot := t.TempDir()
+	source := createMethodologySource(t)
+	setManifestPolicy(t, source, ".opencode/agents/verifier.md", "overwrite_if_unmodified", "overwrite_if_unmodified")
+	writeFile(t, filepath.Join(source, "project_root", "broken.md"), "{{ .Missing }}\n")
+	addManifestMapping(t, source, map[string]any{
+		"source":      "project_root/broken.md",
+		"destination": "BROKEN.md",
+		"on_init":     "if_missing",
+		"on_update":   "render",
+	})
+	configureCanonicalSourceFromDir(t, source)
+
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+	verifierPath := filepath.Join(projectRoot, ".opencode", "agents", "verifier.md")
+	before := string(mustReadFile(t, verifierPath))
+
+	writeFile(t, filepath.Join(source, "subagents", "verifier.md"), "---\nmode: subagent\n---\nverifier v2\n")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
+
+	if exitCode != 1 {
+		t.Fatalf("exit code: got %d, want 1 (stdout=%q)", exitCode, stdout.String())
+	}
+	if strings.Contains(stdout.String(), "updated") {
+		t.Fatalf("nothing should be reported as updated: %q", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "restored previous .methodology") {
+		t.Fatalf("stderr: %q", stderr.String())
+	}
+	if got := string(mustReadFile(t, verifierPath)); got != before {
+		t.Fatalf("verifier.md not rolled back: %q", got)
+	}
+	if got := string(mustReadFile(t, filepath.Join(projectRoot, ".methodology", "subagents", "verifier.md"))); strings.Contains(got, "verifier v2") {
+		t.Fatalf(".methodology not rolled back: %q", got)
+	}
+	matches, _ := filepath.Glob(filepath.Join(projectRoot, ".methodology.*"))
+	if len(matches) != 0 {
+		t.Fatalf("leftover directories: %v", matches)
+	}
+}
+
+func TestRunUpdateRemovePolicyDeletesRetiredFile(t *testing.T) {
+	projectRoot := t.TempDir()
+	source := createMethodologySource(t)
+	configureCanonicalSourceFromDir(t, source)
+
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+
+	setManifestPolicy(t, source, ".opencode/agents/docs-writer.md", "remove", "remove")
+	setManifestPolicy(t, source, ".opencode/agents/investigator.md", "remove", "remove")
+	writeFile(t, filepath.Join(projectRoot, ".opencode", "agents", "investigator.md"), "local custom\n")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "removed: .opencode/agents/docs-writer.md") {
+		t.Fatalf("missing removal: %q", stdout.String())
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, ".opencode", "agents", "docs-writer.md")); !os.IsNotExist(err) {
+		t.Fatalf("docs-writer.md should be removed, stat err=%v", err)
+	}
+	if !strings.Contains(stdout.String(), "notice: retired .opencode/agents/investigator.md was modified locally; kept existing") {
+		t.Fatalf("missing retired notice: %q", stdout.String())
+	}
+	assertFileExists(t, filepath.Join(projectRoot, ".opencode", "agents", "investigator.md"))
+}
+
+func TestRunInitRenderPolicyUsesProjectVariables(t *testing.T) {
+	source := createMethodologySource(t)
+	writeFile(t, filepath.Join(source, "project_root", "local_agents.md"), "# Project: {{ .ProjectName }}\n")
+	setManifestPolicy(t, source, "AGENTS.md", "render", "render")
+	configureCanonicalSourceFromDir(t, source)
+
+	projectRoot := filepath.Join(t.TempDir(), "billing-service")
+	if err := os.MkdirAll(projectRoot, 0o755); err != nil {
+		t.Fatalf("mkdir project: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunInit(context.Background(), nil, projectRoot, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if got := string(mustReadFile(t, filepath.Join(projectRoot, "AGENTS.md"))); got != "# Project: billing-service\n" {
+		t.Fatalf("AGENTS.md: got %q", got)
+	}
+}
+
+func TestRunUpdateReportsRefreshedAndCustomizedProjections(t *testing.T) {
+	projectRoot := t.TempDir()
+	source := createMethodologySource(t)
+	setManifestPolicy(t, source, ".opencode/agents/verifier.md", "if_missing", "overwrite_if_unmodified")
+	setManifestPolicy(t, source, ".opencode/agents/investigator.md", "if_missing", "overwrite_if_unmodified")
+	configureCanonicalSourceFromDir(t, source)
+
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+
+	state := string(mustReadFile(t, filepath.Join(projectRoot, ".methodology", ".spire-sync-state.json")))
+	for _, destination := range []string{"AGENTS.md", "opencode.json", ".opencode/agents/verifier.md"} {
+		if !strings.Contains(state, "\""+destination+"\"") {
+			t.Fatalf("sync state missing projection %s: %q", destination, state)
+		}
+	}
+
+	writeFile(t, filepath.Join(projectRoot, ".opencode", "agents", "investigator.md"), "---\nmode: subagent\n---\nlocal line\n")
+	writeFile(t, filepath.Join(source, "subagents", "verifier.md"), "---\nmode: subagent\n---\nverifier v2\n")
+	writeFile(t, filepath.Join(source, "subagents", "investigator.md"), "---\nmode: subagent\n---\ninvestigator v2\n")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+
+	output := stdout.String()
+	if !strings.Contains(output, "refreshed untouched files:\n- .opencode/agents/verifier.md\n") {
+		t.Fatalf("missing refreshed section: %q", output)
+	}
+	if !strings.Contains(output, "kept customized files:\n- .opencode/agents/investigator.md (+1 -1 lines vs upstream)\n") {
+		t.Fatalf("missing customized section: %q", output)
+	}
+}
+
+func TestRunUpdateNeverOverwriteNoticeFlagsCustomizedFile(t *testing.T) {
+	projectRoot := t.TempDir()
+	source := createMethodologySource(t)
+	configureCanonicalSourceFromDir(t, source)
+
+	if code := RunInit(context.Background(), nil, projectRoot, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("init failed with code %d", code)
+	}
+
+	writeFile(t, filepath.Join(projectRoot, "AGENTS.md"), "# Project\nlocal rule\n")
+	writeFile(t, filepath.Join(source, "project_root", "local_agents.md"), "# Project v2\n")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunUpdate(context.Background(), nil, projectRoot, strings.NewReader(""), false, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "notice: upstream project_root/local_agents.md changed; kept existing AGENTS.md (customized, +2 -1 lines vs upstream)") {
+		t.Fatalf("missing customized notice: %q", stdout.String())
+	}
+}
+
+func addManifestMapping(t *testing.T, sourceDir string, mapping map[string]any) {
+	t.Helper()
+
+	manifestPath := filepath.Join(sourceDir, "project_root", "manifest.json")
+	var manifest map[string]any
+	if err := json.Unmarshal(mustReadFile(t, manifestPath), &manifest); err != nil {
+		t.Fatalf("parse manifest: %v", err)
+	}
+
+	manifest["mappings"] = append(manifest["mappings"].([]any), mapping)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		t.Fatalf("serialize manifest: %v", err)
+	}
+	writeFile(t, manifestPath, string(data))
+}
+
+func addManifestTarget(t *testing.T, sourceDir string, name string, mappings ...map[string]any) {
+	t.Helper()
+
+	manifestPath := filepath.Join(sourceDir, "project_root", "manifest.json")
+	var manifest map[string]any
+	if err := json.Unmarshal(mustReadFile(t, manifestPath), &manifest); err != nil {
+		t.Fatalf("parse manifest: %v", err)
+	}
+
+	targets, _ := manifest["targets"].(map[string]any)
+	if targets == nil {
+		targets = map[string]any{}
+	}
+	targets[name] = map[string]any{"mappings": mappings}
+	manifest["targets"] = targets
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		t.Fatalf("serialize manifest: %v", err)
+	}
+	writeFile(t, manifestPath, string(data))
+}
@@ -1,7 +1,9 @@
 package commands
 
 import (
+	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,12 +13,13 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"opencode-spire/internal/download"
 )
 
 const defaultUpgradeRepo = "niparis/spire"
 
 var (
-	httpClient      = &http.Client{Timeout: 30 * time.Second}
 	runtimeGOOS     = runtime.GOOS
 	runtimeGOARCH   = runtime.GOARCH
 	fetchRelease    = fetchLatestRelease
@@ -40,13 +43,24 @@ type semver struct {
 	patch int
 }
 
-func RunUpgrade(args []string, currentVersion string, stdout io.Writer, stderr io.Writer) int {
-	if len(args) > 0 {
-		fmt.Fprintln(stderr, "usage: spire upgrade")
+func declareUpgradeFlags(flags *flag.FlagSet) *time.Duration {
+	return flags.Duration("timeout", download.DefaultTimeout, "timeout for each download attempt")
+}
+
+func RunUpgrade(ctx context.Context, args []string, currentVersion string, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("upgrade", stderr)
+	timeout := declareUpgradeFlags(flags)
+	if done, code := parseFlags(flags, args, stdout); done {
+		return code
+	}
+	if flags.NArg() > 0 {
+		printUsage(stderr, "upgrade")
 		return 1
 	}
 
-	release, err := fetchRelease(upgradeRepoName)
+	client := download.New(download.Options{Timeout: *timeout, Progress: progressOutput(stderr)})
+
+	release, err := fetchRelease(ctx, client, upgradeRepoName)
 	if err != nil {
 		fmt.Fprintf(stderr, "failed to check latest release: %v\n", err)
 		return 1
@@ -76,7 +90,7 @@ func RunUpgrade(args []string, currentVersion string, stdout io.Writer, stderr i
 		return 1
 	}
 
-	if err := replaceBinary(assetURL); err != nil {
+	if err := replaceBinary(ctx, client, assetURL); err != nil {
 		fmt.Fprintf(stderr, "failed to replace current executable: %v\n", err)
 		return 1
 	}
@@ -90,27 +104,26 @@ func RunUpgrade(args []string, currentVersion string, stdout io.Writer, stderr i
 	return 0
 }
 
-func fetchLatestRelease(repo string) (latestRelease, error) {
+func fetchLatestRelease(ctx context.Context, client *download.Client, repo string) (latestRelease, error) {
 	url := fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", repo)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return latestRelease{}, fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("User-Agent", "spire-upgrade")
 
-	resp, err := httpClient.Do(req)
+	resp, err := client.Fetch(ctx, req)
 	if err != nil {
 		return latestRelease{}, fmt.Errorf("request latest release: %w", err)
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		return latestRelease{}, fmt.Errorf("unexpected status %s", resp.Status)
 	}
 
 	var release latestRelease
-	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+	if err := json.Unmarshal(resp.Body, &release); err != nil {
 		return latestRelease{}, fmt.Errorf("decode latest release response: %w", err)
 	}
 
@@ -121,7 +134,7 @@ func fetchLatestRelease(repo string) (latestRelease, error) {
 	return release, nil
 }
 
-func replaceCurrentBinary(downloadURL string) error {
+func replaceCurrentBinary(ctx context.Context, client *download.Client, downloadURL string) error {
 	execPath, err := os.Executable()
 	if err != nil {
 		return fmt.Errorf("resolve current executable: %w", err)
@@ -144,29 +157,17 @@ func replaceCurrentBinary(downloadURL string) error {
 		_ = os.Remove(tmpPath)
 	}
 
-	req, err := http.NewRequest(http.MethodGet, downloadURL, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
 	if err != nil {
 		cleanup()
 		return fmt.Errorf("create download request: %w", err)
 	}
 	req.Header.Set("User-Agent", "spire-upgrade")
 
-	resp, err := httpClient.Do(req)
-	if err != nil {
+	if _, err := client.ToFile(ctx, req, tmp, "downloading spire"); err != nil {
 		cleanup()
 		return fmt.Errorf("download replacement binary: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		cleanup()
-		return fmt.Errorf("download replacement binary: unexpected status %s", resp.Status)
-	}
-
-	if _, err := io.Copy(tmp, resp.Body); err != nil {
-		cleanup()
-		return fmt.Errorf("write replacement binary: %w", err)
-	}
 
 	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
 		cleanup()
@@ -2,15 +2,18 @@ package commands
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
 	"testing"
+
+	"opencode-spire/internal/download"
 )
 
 func TestRunUpgradeRejectsUnexpectedArgs(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunUpgrade([]string{"--check"}, "0.2.0", &stdout, &stderr)
+	exitCode := RunUpgrade(context.Background(), []string{"--check"}, "0.2.0", &stdout, &stderr)
 
 	if exitCode != 1 {
 		t.Fatalf("exit code: got %d, want 1", exitCode)
@@ -26,7 +29,7 @@ func TestRunUpgradeNoopWhenLatestIsNotNewer(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunUpgrade(nil, "0.2.0", &stdout, &stderr)
+	exitCode := RunUpgrade(context.Background(), nil, "0.2.0", &stdout, &stderr)
 
 	if exitCode != 0 {
 		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
@@ -38,7 +41,7 @@ func TestRunUpgradeNoopWhenLatestIsNotNewer(t *testing.T) {
 
 func TestRunUpgradeWhenNewerReleaseAvailable(t *testing.T) {
 	var replacedWith string
-	restore := overrideUpgradeDeps(t, latestRelease{TagName: "v0.3.0", Assets: []releaseAsset{{Name: "spire_darwin_arm64", URL: "https://example.invalid/spire-new"}}}, func(url string) error {
+	restore := overrideUpgradeDeps(t, latestRelease{TagName: "v0.3.0", Assets: []releaseAsset{{Name: "spire_darwin_arm64", URL: "https://example.invalid/spire-new"}}}, func(ctx context.Context, client *download.Client, url string) error {
 		replacedWith = url
 		return nil
 	})
@@ -46,7 +49,7 @@ func TestRunUpgradeWhenNewerReleaseAvailable(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunUpgrade(nil, "0.2.0", &stdout, &stderr)
+	exitCode := RunUpgrade(context.Background(), nil, "0.2.0", &stdout, &stderr)
 
 	if exitCode != 0 {
 		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
@@ -65,7 +68,7 @@ func TestRunUpgradeFailsWhenAssetMissing(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunUpgrade(nil, "0.2.0", &stdout, &stderr)
+	exitCode := RunUpgrade(context.Background(), nil, "0.2.0", &stdout, &stderr)
 
 	if exitCode != 1 {
 		t.Fatalf("exit code: got %d, want 1", exitCode)
@@ -77,7 +80,7 @@ func TestRunUpgradeFailsWhenAssetMissing(t *testing.T) {
 
 func TestRunUpgradeFromDevBuild(t *testing.T) {
 	var called bool
-	restore := overrideUpgradeDeps(t, latestRelease{TagName: "v0.4.0", Assets: []releaseAsset{{Name: "spire_darwin_arm64", URL: "https://example.invalid/spire-new"}}}, func(url string) error {
+	restore := overrideUpgradeDeps(t, latestRelease{TagName: "v0.4.0", Assets: []releaseAsset{{Name: "spire_darwin_arm64", URL: "https://example.invalid/spire-new"}}}, func(ctx context.Context, client *download.Client, url string) error {
 		called = true
 		return nil
 	})
@@ -85,7 +88,7 @@ func TestRunUpgradeFromDevBuild(t *testing.T) {
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunUpgrade(nil, "dev", &stdout, &stderr)
+	exitCode := RunUpgrade(context.Background(), nil, "dev", &stdout, &stderr)
 
 	if exitCode != 0 {
 		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
@@ -100,14 +103,14 @@ func TestRunUpgradeFromDevBuild(t *testing.T) {
 
 func TestRunUpgradeFetchFailure(t *testing.T) {
 	prevFetch := fetchRelease
-	fetchRelease = func(repo string) (latestRelease, error) {
+	fetchRelease = func(ctx context.Context, client *download.Client, repo string) (latestRelease, error) {
 		return latestRelease{}, fmt.Errorf("boom")
 	}
 	t.Cleanup(func() { fetchRelease = prevFetch })
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := RunUpgrade(nil, "0.2.0", &stdout, &stderr)
+	exitCode := RunUpgrade(context.Background(), nil, "0.2.0", &stdout, &stderr)
 
 	if exitCode != 1 {
 		t.Fatalf("exit code: got %d, want 1", exitCode)
@@ -117,7 +120,7 @@ func TestRunUpgradeFetchFailure(t *testing.T) {
 	}
 }
 
-func overrideUpgradeDeps(t *testing.T, release latestRelease, replace func(url string) error) func() {
+func overrideUpgradeDeps(t *testing.T, release latestRelease, replace func(ctx context.Context, client *download.Client, url string) error) func() {
 	t.Helper()
 
 	prevFetch := fetchRelease
@@ -125,11 +128,11 @@ func overrideUpgradeDeps(t *testing.T, release latestRelease, replace func(url s
 	prevGOOS := runtimeGOOS
 	prevGOARCH := runtimeGOARCH
 
-	fetchRelease = func(repo string) (latestRelease, error) {
+	fetchRelease = func(ctx context.Context, client *download.Client, repo string) (latestRelease, error) {
 		return release, nil
 	}
 	if replace == nil {
-		replaceBinary = func(url string) error {
+		replaceBinary = func(ctx context.Context, client *download.Client, url string) error {
 			t.Fatalf("replaceBinary should not be called")
 			return nil
 		}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"opencode-spire/internal/scaffold"
+)
+
+func RunValidate(ctx context.Context, args []string, projectRoot string, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("validate", stderr)
+	if done, code := parseFlags(flags, args, stdout); done {
+		return code
+	}
+	if flags.NArg() > 0 {
+		printUsage(stderr, "validate")
+		return 1
+	}
+
+	issues, err := scaffold.ValidateOpencodeConfig(projectRoot)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to validate opencode configuration: %v\n", err)
+		return 1
+	}
+
+	if len(issues) == 0 {
+		fmt.Fprintln(stdout, "opencode configuration is valid")
+		return 0
+	}
+
+	fmt.Fprintln(stderr, "opencode configuration issues:")
+	for _, issue := range issues {
+		fmt.Fprintf(stderr, "- %s\n", issue)
+	}
+	return 1
+}
+
+func warnOpencodeIssues(projectRoot string, stderr io.Writer) {
+	issues, err := scaffold.ValidateOpencodeConfig(projectRoot)
+	if err != nil {
+		fmt.Fprintf(stderr, "warning: failed to validate opencode configuration: %v\n", err)
+		return
+	}
+
+	for _, issue := range issues {
+		fmt.Fprintf(stderr, "warning: %s\n", issue)
+	}
+}
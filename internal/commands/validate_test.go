@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunValidateCleanProject(t *testing.T) {
+	projectRoot := t.TempDir()
+	writeFile(t, filepath.Join(projectRoot, "AGENTS.md"), "# Project\n")
+	writeFile(t, filepath.Join(projectRoot, "opencode.json"), "{\n  \"instructions\": [\"AGENTS.md\"]\n}\n")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunValidate(context.Background(), nil, projectRoot, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "opencode configuration is valid") {
+		t.Fatalf("stdout: %q", stdout.String())
+	}
+}
+
+func TestRunValidateReportsIssues(t *testing.T) {
+	projectRoot := t.TempDir()
+	writeFile(t, filepath.Join(projectRoot, "opencode.json"), "{\n  \"instructions\": [\"specs/PRODUCT.md\"]\n}\n")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunValidate(context.Background(), nil, projectRoot, &stdout, &stderr)
+
+	if exitCode != 1 {
+		t.Fatalf("exit code: got %d, want 1", exitCode)
+	}
+	if !strings.Contains(stderr.String(), `opencode.json: instruction "specs/PRODUCT.md" not found`) {
+		t.Fatalf("stderr: %q", stderr.String())
+	}
+}
+
+func TestRunInitWarnsAboutMissingAgentReferences(t *testing.T) {
+	source := createMethodologySource(t)
+	configureCanonicalSourceFromDir(t, source)
+	projectRoot := t.TempDir()
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := RunInit(context.Background(), nil, projectRoot, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("exit code: got %d, stderr=%q", exitCode, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), `warning: .opencode/agents/featureplanner.md: referenced file ".methodology/agents/FEATURE_PLANNER.md" not found`) {
+		t.Fatalf("stderr: %q", stderr.String())
+	}
+}
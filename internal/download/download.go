@@ -0,0 +1,311 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	DefaultTimeout  = 30 * time.Second
+	DefaultAttempts = 4
+)
+
+var defaultBackoff = 500 * time.Millisecond
+
+// errRangeMismatch marks a 206 response that does not continue where the
+// previous attempt stopped; the transfer is restarted from the beginning.
+var errRangeMismatch = errors.New("server resumed the transfer at the wrong offset")
+
+type Options struct {
+	Timeout  time.Duration
+	Attempts int
+	Backoff  time.Duration
+	Progress io.Writer
+}
+
+type Client struct {
+	http     *http.Client
+	attempts int
+	backoff  time.Duration
+	progress io.Writer
+}
+
+type Response struct {
+	StatusCode int
+	Status     string
+	Header     http.Header
+	Body       []byte
+	Bytes      int64
+}
+
+type statusError struct {
+	status string
+	code   int
+}
+
+func (e *statusError) Error() string {
+	return "unexpected status " + e.status
+}
+
+func SetBackoffForTesting(backoff time.Duration) func() {
+	prev := defaultBackoff
+	defaultBackoff = backoff
+	return func() { defaultBackoff = prev }
+}
+
+func New(opts Options) *Client {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.Attempts <= 0 {
+		opts.Attempts = DefaultAttempts
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = defaultBackoff
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+
+	return &Client{
+		http:     &http.Client{Timeout: opts.Timeout, Transport: transport},
+		attempts: opts.Attempts,
+		backoff:  opts.Backoff,
+		progress: opts.Progress,
+	}
+}
+
+// Fetch performs req and reads the whole body into memory, retrying transient
+// failures. Non-2xx responses other than 304 are returned as errors.
+func (c *Client) Fetch(ctx context.Context, req *http.Request) (*Response, error) {
+	var result *Response
+	err := c.retry(ctx, func() error {
+		resp, err := c.http.Do(req.Clone(ctx))
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if err := checkStatus(resp); err != nil {
+			return err
+		}
+
+		var body bytes.Buffer
+		if resp.StatusCode != http.StatusNotModified {
+			if _, err := io.Copy(&body, resp.Body); err != nil {
+				return err
+			}
+		}
+
+		result = &Response{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Header:     resp.Header,
+			Body:       body.Bytes(),
+			Bytes:      int64(body.Len()),
+		}
+		return nil
+	})
+	return result, err
+}
+
+// ToFile streams the body of req into file. When a transfer breaks off it is
+// resumed with a Range request; servers that ignore the range restart it.
+func (c *Client) ToFile(ctx context.Context, req *http.Request, file *os.File, label string) (*Response, error) {
+	var written int64
+	var result *Response
+
+	err := c.retry(ctx, func() error {
+		attemptReq := req.Clone(ctx)
+		if written > 0 {
+			attemptReq.Header.Set("Range", "bytes="+strconv.FormatInt(written, 10)+"-")
+		}
+
+		resp, err := c.http.Do(attemptReq)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if err := checkStatus(resp); err != nil {
+			return err
+		}
+
+		if resp.StatusCode == http.StatusNotModified {
+			result = &Response{StatusCode: resp.StatusCode, Status: resp.Status, Header: resp.Header}
+			return nil
+		}
+
+		if resp.StatusCode == http.StatusPartialContent && rangeStart(resp.Header.Get("Content-Range")) != written {
+			if err := file.Truncate(0); err != nil {
+				return permanent(fmt.Errorf("truncate %q: %w", file.Name(), err))
+			}
+			written = 0
+			return errRangeMismatch
+		}
+		if resp.StatusCode != http.StatusPartialContent && written > 0 {
+			if err := file.Truncate(0); err != nil {
+				return permanent(fmt.Errorf("truncate %q: %w", file.Name(), err))
+			}
+			written = 0
+		}
+		if _, err := file.Seek(written, io.SeekStart); err != nil {
+			return permanent(fmt.Errorf("seek %q: %w", file.Name(), err))
+		}
+
+		n, err := io.Copy(fileWriter{file}, c.meter(resp.Body, label, written))
+		written += n
+		if err != nil {
+			return err
+		}
+
+		status := resp.StatusCode
+		if status == http.StatusPartialContent {
+			status = http.StatusOK
+		}
+		result = &Response{StatusCode: status, Status: resp.Status, Header: resp.Header, Bytes: written}
+		return nil
+	})
+	return result, err
+}
+
+// fileWriter marks write errors permanent: only reads from the response body
+// are transfer failures worth retrying or reporting as an Error.
+type fileWriter struct {
+	file *os.File
+}
+
+func (w fileWriter) Write(buf []byte) (int, error) {
+	n, err := w.file.Write(buf)
+	if err != nil {
+		return n, permanent(fmt.Errorf("write %q: %w", w.file.Name(), err))
+	}
+	return n, nil
+}
+
+// Error reports a transfer that failed on the network or with an HTTP error
+// status, as opposed to a local failure such as writing the output file.
+type Error struct {
+	Err error
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+func (e *Error) Unwrap() error { return e.Err }
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+func permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+func (c *Client) retry(ctx context.Context, attempt func() error) error {
+	delay := c.backoff
+	var err error
+
+	for i := 1; i <= c.attempts; i++ {
+		err = attempt()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !transient(err) {
+			break
+		}
+		if i == c.attempts {
+			return &Error{Err: fmt.Errorf("%w (gave up after %d attempts)", err, i)}
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+
+	var perm *permanentError
+	if errors.As(err, &perm) {
+		return perm.err
+	}
+	return &Error{Err: err}
+}
+
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 || resp.StatusCode == http.StatusNotModified {
+		return nil
+	}
+	return &statusError{status: resp.Status, code: resp.StatusCode}
+}
+
+// rangeStart returns the first byte offset of a "bytes first-last/total"
+// Content-Range value, or -1 when it cannot be parsed.
+func rangeStart(contentRange string) int64 {
+	spec, ok := strings.CutPrefix(contentRange, "bytes ")
+	if !ok {
+		return -1
+	}
+	first, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return -1
+	}
+	start, err := strconv.ParseInt(strings.TrimSpace(first), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return start
+}
+
+// transient reports whether a retry could succeed: timeouts, failed dials,
+// reset connections, truncated bodies, 429 and 5xx. Certificate errors,
+// unsupported schemes and other client-side problems are final.
+func transient(err error) bool {
+	var perm *permanentError
+	if errors.As(err, &perm) {
+		return false
+	}
+
+	var status *statusError
+	if errors.As(err, &status) {
+		return status.code == http.StatusTooManyRequests || status.code >= 500
+	}
+
+	var certErr *tls.CertificateVerificationError
+	if errors.As(err, &certErr) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, errRangeMismatch) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
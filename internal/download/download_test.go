@@ -0,0 +1,245 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchRetriesTransientStatus(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+
+	client := New(Options{Backoff: time.Millisecond})
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := client.Fetch(context.Background(), req)
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if string(resp.Body) != "ok" || calls.Load() != 3 {
+		t.Fatalf("body=%q calls=%d", resp.Body, calls.Load())
+	}
+}
+
+func TestFetchDoesNotRetryClientErrors(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	client := New(Options{Backoff: time.Millisecond})
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	_, err := client.Fetch(context.Background(), req)
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("expected 404 error, got %v", err)
+	}
+	var downloadErr *Error
+	if !errors.As(err, &downloadErr) {
+		t.Fatalf("expected *Error, got %T", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("calls: got %d, want 1", calls.Load())
+	}
+}
+
+func TestToFileResumesInterruptedTransfer(t *testing.T) {
+	t.Parallel()
+
+	payload := strings.Repeat("spire", 1000)
+	var ranges []string
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Content-Length", strconv.Itoa(len(payload)))
+			_, _ = w.Write([]byte(payload[:100]))
+			return
+		}
+
+		ranges = append(ranges, r.Header.Get("Range"))
+		w.Header().Set("Content-Range", "bytes 100-"+strconv.Itoa(len(payload)-1)+"/"+strconv.Itoa(len(payload)))
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write([]byte(payload[100:]))
+	}))
+	t.Cleanup(server.Close)
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "download"))
+	if err != nil {
+		t.Fatalf("create file: %v", err)
+	}
+	defer file.Close()
+
+	client := New(Options{Backoff: time.Millisecond})
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := client.ToFile(context.Background(), req, file, "")
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+
+	got, err := os.ReadFile(file.Name())
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	if string(got) != payload || resp.Bytes != int64(len(payload)) {
+		t.Fatalf("downloaded %d bytes, want %d", len(got), len(payload))
+	}
+	if len(ranges) != 1 || ranges[0] != "bytes=100-" {
+		t.Fatalf("range headers: %v", ranges)
+	}
+}
+
+func TestFetchStopsOnContextCancel(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := New(Options{Backoff: time.Hour})
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+
+	time.AfterFunc(20*time.Millisecond, cancel)
+	_, err := client.Fetch(ctx, req)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[int64]string{
+		512:             "512 B",
+		2048:            "2.0 KiB",
+		5 * 1024 * 1024: "5.0 MiB",
+	}
+	for input, want := range tests {
+		if got := formatBytes(input); got != want {
+			t.Fatalf("formatBytes(%d): got %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestToFileRestartsOnMismatchedContentRange(t *testing.T) {
+	t.Parallel()
+
+	payload := strings.Repeat("spire", 1000)
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.Header().Set("Content-Length", strconv.Itoa(len(payload)))
+			_, _ = w.Write([]byte(payload[:100]))
+		case 2:
+			w.Header().Set("Content-Range", "bytes 50-"+strconv.Itoa(len(payload)-1)+"/"+strconv.Itoa(len(payload)))
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write([]byte(payload[50:]))
+		default:
+			if r.Header.Get("Range") != "" {
+				t.Errorf("restart should not send a Range header, got %q", r.Header.Get("Range"))
+			}
+			_, _ = w.Write([]byte(payload))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "download"))
+	if err != nil {
+		t.Fatalf("create file: %v", err)
+	}
+	defer file.Close()
+
+	client := New(Options{Backoff: time.Millisecond})
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	if _, err := client.ToFile(context.Background(), req, file, ""); err != nil {
+		t.Fatalf("download: %v", err)
+	}
+
+	got, err := os.ReadFile(file.Name())
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	if string(got) != payload {
+		t.Fatalf("downloaded %d bytes, want %d", len(got), len(payload))
+	}
+}
+
+func TestFetchDoesNotRetryPermanentFailures(t *testing.T) {
+	t.Parallel()
+
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(tlsServer.Close)
+
+	for name, url := range map[string]string{
+		"unsupported scheme": "ftp://example.invalid/spire.tar.gz",
+		"untrusted cert":     tlsServer.URL,
+	} {
+		client := New(Options{Backoff: time.Millisecond})
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		_, err := client.Fetch(context.Background(), req)
+		if err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+		if strings.Contains(err.Error(), "gave up after") {
+			t.Fatalf("%s: should not be retried: %v", name, err)
+		}
+	}
+}
+
+func TestToFileWriteFailureIsNotATransferError(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = w.Write([]byte("spire"))
+	}))
+	t.Cleanup(server.Close)
+
+	path := filepath.Join(t.TempDir(), "download")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatalf("create file: %v", err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open file: %v", err)
+	}
+	defer file.Close()
+
+	client := New(Options{Backoff: time.Millisecond})
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	_, err = client.ToFile(context.Background(), req, file, "")
+	if err == nil {
+		t.Fatal("expected a write error")
+	}
+
+	var downloadErr *Error
+	if errors.As(err, &downloadErr) {
+		t.Fatalf("write failure reported as a transfer error: %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("requests: got %d, want 1", calls.Load())
+	}
+}
@@ -0,0 +1,69 @@
+package download
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const progressInterval = 100 * time.Millisecond
+
+type progressReader struct {
+	reader  io.Reader
+	out     io.Writer
+	label   string
+	total   int64
+	printed time.Time
+}
+
+// IsTerminal reports whether w is a character device, so progress lines with
+// carriage returns render instead of cluttering logs.
+func IsTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func (c *Client) meter(reader io.Reader, label string, offset int64) io.Reader {
+	if c.progress == nil || label == "" {
+		return reader
+	}
+	return &progressReader{reader: reader, out: c.progress, label: label, total: offset}
+}
+
+func (p *progressReader) Read(buf []byte) (int, error) {
+	n, err := p.reader.Read(buf)
+	p.total += int64(n)
+
+	now := time.Now()
+	if err == io.EOF {
+		fmt.Fprintf(p.out, "\r%s: %s\n", p.label, formatBytes(p.total))
+	} else if now.Sub(p.printed) >= progressInterval {
+		p.printed = now
+		fmt.Fprintf(p.out, "\r%s: %s", p.label, formatBytes(p.total))
+	}
+
+	return n, err
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for value := n / unit; value >= unit; value /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
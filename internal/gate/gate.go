@@ -0,0 +1,310 @@
+package gate
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"opencode-spire/internal/approval"
+	"opencode-spire/internal/session"
+	"opencode-spire/internal/tasks"
+	"opencode-spire/internal/verification"
+)
+
+// Gate numbers from the methodology that spire can check.
+const (
+	GateAudit        = 1
+	GateApproval     = 2
+	GateVerification = 4
+)
+
+var Gates = []int{GateAudit, GateApproval, GateVerification}
+
+var auditVerdictPattern = regexp.MustCompile(`(?i)^\**VERDICT\**:\**\s*(.*)$`)
+
+// nonSourcePrefixes are the paths that hold methodology artifacts rather than
+// product code; changing them does not start implementation.
+var nonSourcePrefixes = []string{"specs/", "changes/", "archive/", ".methodology/", ".opencode/", ".claude/", ".github/", ".spire.json"}
+
+type Status int
+
+const (
+	Passed Status = iota
+	Failed
+	Skipped
+)
+
+type Result struct {
+	Gate    int
+	Feature string
+	Status  Status
+	// File is the project-relative path the result is about.
+	File    string
+	Message string
+}
+
+// Input describes what to check. Changed is the PR's changed files, or nil
+// outside a PR; the verification gate only applies to a PR. A PR's source
+// changes count against a feature when the PR also touches changes/<slug>/ or
+// the feature was Selected by name; source changes that match no feature fail
+// gate 4 on their own.
+type Input struct {
+	ProjectRoot string
+	Features    []string
+	Selected    bool
+	Gates       []int
+	Changed     []string
+}
+
+// Evaluate checks each feature against each requested gate:
+//
+//   - gate 1: once PLAN.md or TASKS.md exists, the spec audit exists and PASSes
+//   - gate 2: once implementation has started, PLAN.md and TASKS.md are
+//     approved and unchanged since
+//   - gate 4: a PR that touches the feature has a READY FOR PR verdict, and a
+//     PR that changes source files touches some feature
+func Evaluate(input Input) ([]Result, error) {
+	var results []Result
+	for _, slug := range input.Features {
+		for _, number := range input.Gates {
+			var result Result
+			var err error
+			switch number {
+			case GateAudit:
+				result, err = checkAudit(input.ProjectRoot, slug)
+			case GateApproval:
+				result, err = checkApproval(input, slug)
+			case GateVerification:
+				result, err = checkVerification(input, slug)
+			default:
+				return nil, fmt.Errorf("no checks for gate %d", number)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("gate %d for %s: %w", number, slug, err)
+			}
+			result.Gate = number
+			result.Feature = slug
+			results = append(results, result)
+		}
+	}
+
+	if result, ok := unattributedSource(input); ok {
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// unattributedSource fails gate 4 for a PR whose source changes cannot be
+// tied to any feature, which would otherwise skip verification entirely.
+func unattributedSource(input Input) (Result, bool) {
+	if input.Selected || input.Changed == nil || !slices.Contains(input.Gates, GateVerification) {
+		return Result{}, false
+	}
+	file := firstSource(input.Changed)
+	if file == "" {
+		return Result{}, false
+	}
+	for _, slug := range input.Features {
+		if touches(input.Changed, "changes/"+slug+"/") {
+			return Result{}, false
+		}
+	}
+	return Result{
+		Gate:    GateVerification,
+		Feature: "pull request",
+		Status:  Failed,
+		File:    file,
+		Message: "source files changed but no feature's changes/<slug>/ did; rerun with --feature <feature>",
+	}, true
+}
+
+func checkAudit(projectRoot string, slug string) (Result, error) {
+	auditFile := path.Join("specs", "feature-"+slug+"-AUDIT.md")
+	planned, plannedFile, err := firstExisting(projectRoot, changesFile(slug, "PLAN.md"), changesFile(slug, tasks.Filename))
+	if err != nil {
+		return Result{}, err
+	}
+	if !planned {
+		return Result{Status: Skipped, File: auditFile, Message: "no PLAN.md or TASKS.md yet"}, nil
+	}
+
+	verdict, found, err := auditVerdict(filepath.Join(projectRoot, auditFile))
+	if err != nil {
+		return Result{}, err
+	}
+	switch {
+	case !found:
+		return Result{Status: Failed, File: plannedFile, Message: fmt.Sprintf("%s exists but %s is missing", path.Base(plannedFile), auditFile)}, nil
+	case verdict != "PASS":
+		if verdict == "" {
+			verdict = "missing"
+		}
+		return Result{Status: Failed, File: auditFile, Message: fmt.Sprintf("audit verdict is %s, want PASS before planning", verdict)}, nil
+	default:
+		return Result{Status: Passed, File: auditFile, Message: "audit PASS"}, nil
+	}
+}
+
+func checkApproval(input Input, slug string) (Result, error) {
+	started, err := implementationStarted(input, slug)
+	if err != nil {
+		return Result{}, err
+	}
+	if !started {
+		return Result{Status: Skipped, File: changesFile(slug, "PLAN.md"), Message: "implementation has not started"}, nil
+	}
+
+	statuses, err := approval.Status(input.ProjectRoot, slug)
+	if err != nil {
+		return Result{}, err
+	}
+	for _, status := range statuses {
+		file := changesFile(slug, status.File)
+		switch status.State {
+		case approval.Missing:
+			return Result{Status: Failed, File: file, Message: fmt.Sprintf("implementation started without %s", status.File)}, nil
+		case approval.Unapproved:
+			return Result{Status: Failed, File: file, Message: fmt.Sprintf("%s is not approved; implementation must wait for spire approve", status.File)}, nil
+		case approval.Stale:
+			return Result{Status: Failed, File: file, Message: fmt.Sprintf("%s changed after %s approved it", status.File, status.Record.Approver)}, nil
+		}
+	}
+	return Result{Status: Passed, File: changesFile(slug, "PLAN.md"), Message: "PLAN.md and TASKS.md approved"}, nil
+}
+
+func checkVerification(input Input, slug string) (Result, error) {
+	file := changesFile(slug, verification.Filename)
+	if input.Changed == nil {
+		return Result{Status: Skipped, File: file, Message: "not a pull request"}, nil
+	}
+	if !touches(input.Changed, "changes/"+slug+"/") && !(input.Selected && touchesSource(input.Changed)) {
+		return Result{Status: Skipped, File: file, Message: "pull request does not touch this feature"}, nil
+	}
+
+	report, err := verification.Load(input.ProjectRoot, slug)
+	if err != nil {
+		return Result{}, err
+	}
+	switch {
+	case report == nil:
+		return Result{Status: Failed, File: changesFile(slug, "PLAN.md"), Message: fmt.Sprintf("%s is missing; run verification before merge", verification.Filename)}, nil
+	case !report.Ready():
+		verdict := report.Verdict
+		if verdict == "" {
+			verdict = "missing"
+		}
+		return Result{Status: Failed, File: file, Message: fmt.Sprintf("verdict is %s, want %s before merge", verdict, verification.VerdictReady)}, nil
+	default:
+		return Result{Status: Passed, File: file, Message: verification.VerdictReady}, nil
+	}
+}
+
+// implementationStarted reports whether the feature has a checked task, a
+// SESSION.md that records real work under Completed or In Progress, or a PR
+// that changes source files on the feature's behalf. The SESSION.md that
+// spire new scaffolds does not count.
+func implementationStarted(input Input, slug string) (bool, error) {
+	list, err := tasks.Load(input.ProjectRoot, slug)
+	if err != nil {
+		return false, err
+	}
+	if list != nil && list.Progress().Done > 0 {
+		return true, nil
+	}
+
+	parsed, err := session.Load(input.ProjectRoot, slug)
+	if err != nil {
+		return false, err
+	}
+	if parsed != nil && parsed.WorkRecorded() {
+		return true, nil
+	}
+	return touchesSource(input.Changed) && (input.Selected || touches(input.Changed, "changes/"+slug+"/")), nil
+}
+
+func auditVerdict(path string) (string, bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("open audit %q: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		match := auditVerdictPattern.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil {
+			continue
+		}
+		value := strings.ToUpper(strings.TrimSpace(match[1]))
+		for _, verdict := range []string{"PASS", "CONDITIONAL", "FAIL"} {
+			if strings.HasPrefix(value, verdict) {
+				return verdict, true, nil
+			}
+		}
+		return "", true, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return "", false, fmt.Errorf("scan audit %q: %w", path, err)
+	}
+	return "", true, nil
+}
+
+func firstExisting(projectRoot string, files ...string) (bool, string, error) {
+	for _, file := range files {
+		_, err := os.Stat(filepath.Join(projectRoot, file))
+		if err == nil {
+			return true, file, nil
+		}
+		if !os.IsNotExist(err) {
+			return false, "", fmt.Errorf("stat %q: %w", file, err)
+		}
+	}
+	return false, "", nil
+}
+
+func changesFile(slug string, name string) string {
+	return path.Join("changes", slug, name)
+}
+
+func touches(changed []string, prefix string) bool {
+	for _, file := range changed {
+		if strings.HasPrefix(file, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// touchesSource reports whether any changed file is product code rather than
+// a methodology artifact or markdown.
+func touchesSource(changed []string) bool {
+	return firstSource(changed) != ""
+}
+
+// firstSource returns the first changed file that is product code.
+func firstSource(changed []string) string {
+	for _, file := range changed {
+		if strings.HasSuffix(file, ".md") {
+			continue
+		}
+		artifact := false
+		for _, prefix := range nonSourcePrefixes {
+			if strings.HasPrefix(file, prefix) {
+				artifact = true
+				break
+			}
+		}
+		if !artifact {
+			return file
+		}
+	}
+	return ""
+}
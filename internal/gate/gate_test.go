@@ -0,0 +1,165 @@
+package gate
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"opencode-spire/internal/approval"
+)
+
+func TestEvaluateGates(t *testing.T) {
+	projectRoot := t.TempDir()
+	// 001 is planned without an audit and implemented without approval.
+	write(t, projectRoot, "changes/001-alpha/PLAN.md", "# Plan\n")
+	write(t, projectRoot, "changes/001-alpha/TASKS.md", "- [x] T1: start\n")
+	// 002 passed its audit, is approved and verified.
+	write(t, projectRoot, "specs/feature-002-beta-AUDIT.md", "SPEC AUDIT: beta\nVERDICT: PASS (44/50)\n")
+	write(t, projectRoot, "changes/002-beta/PLAN.md", "# Plan\n")
+	write(t, projectRoot, "changes/002-beta/TASKS.md", "- [ ] T1: start\n")
+	if _, err := approval.Approve(projectRoot, "002-beta", approval.Files, "Ada <ada@example.com>", time.Now()); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	write(t, projectRoot, "changes/002-beta/TASKS.md", "- [x] T1: start\n")
+	write(t, projectRoot, "changes/002-beta/SESSION.md", "# Session\n")
+	write(t, projectRoot, "changes/002-beta/VERIFICATION_REPORT.md", "## VERDICT\nREADY FOR PR\n")
+	// 003 has a CONDITIONAL audit and is only a spec otherwise.
+	write(t, projectRoot, "specs/feature-003-gamma-AUDIT.md", "VERDICT: CONDITIONAL (35/50)\n")
+	write(t, projectRoot, "changes/003-gamma/TASKS.md", "- [ ] T1: start\n")
+
+	results, err := Evaluate(Input{
+		ProjectRoot: projectRoot,
+		Features:    []string{"001-alpha", "002-beta", "003-gamma"},
+		Gates:       Gates,
+		Changed:     []string{"internal/alpha.go", "changes/001-alpha/SESSION.md", "changes/002-beta/SESSION.md"},
+	})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+
+	var text bytes.Buffer
+	WriteText(&text, results)
+	want := `FAIL gate 1 001-alpha: PLAN.md exists but specs/feature-001-alpha-AUDIT.md is missing
+FAIL gate 2 001-alpha: PLAN.md is not approved; implementation must wait for spire approve
+FAIL gate 4 001-alpha: VERIFICATION_REPORT.md is missing; run verification before merge
+ok   gate 1 002-beta: audit PASS
+ok   gate 2 002-beta: PLAN.md and TASKS.md approved
+ok   gate 4 002-beta: READY FOR PR
+FAIL gate 1 003-gamma: audit verdict is CONDITIONAL, want PASS before planning
+skip gate 2 003-gamma: implementation has not started
+skip gate 4 003-gamma: pull request does not touch this feature
+`
+	if text.String() != want {
+		t.Fatalf("results:\n%s\nwant:\n%s", text.String(), want)
+	}
+	if Failures(results) != 4 {
+		t.Fatalf("failures: got %d", Failures(results))
+	}
+
+	var annotations bytes.Buffer
+	WriteAnnotations(&annotations, results)
+	if first := strings.SplitN(annotations.String(), "\n", 2)[0]; first != "::error file=changes/001-alpha/PLAN.md,title=Gate 1 (001-alpha)::PLAN.md exists but specs/feature-001-alpha-AUDIT.md is missing" {
+		t.Fatalf("annotation: %q", first)
+	}
+
+	var junit bytes.Buffer
+	if err := WriteJUnit(&junit, results); err != nil {
+		t.Fatalf("WriteJUnit: %v", err)
+	}
+	for _, want := range []string{`<testsuite name="spire gate check" tests="9" failures="4" skipped="2">`, `<testcase name="gate 4" classname="003-gamma" file="changes/003-gamma/VERIFICATION_REPORT.md">`, `<failure message="audit verdict is CONDITIONAL, want PASS before planning">`} {
+		if !strings.Contains(junit.String(), want) {
+			t.Fatalf("junit missing %q:\n%s", want, junit.String())
+		}
+	}
+}
+
+func TestApprovalGateRejectsEditedPlan(t *testing.T) {
+	projectRoot := t.TempDir()
+	write(t, projectRoot, "changes/001-alpha/PLAN.md", "# Plan\n")
+	write(t, projectRoot, "changes/001-alpha/TASKS.md", "- [ ] T1: start\n")
+	write(t, projectRoot, "changes/001-alpha/SESSION.md", "# Session\n\n## In Progress\n- T1 start\n")
+	if _, err := approval.Approve(projectRoot, "001-alpha", approval.Files, "Ada", time.Now()); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	write(t, projectRoot, "changes/001-alpha/PLAN.md", "# Plan\nNew scope.\n")
+
+	results, err := Evaluate(Input{ProjectRoot: projectRoot, Features: []string{"001-alpha"}, Gates: []int{GateApproval}})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if len(results) != 1 || results[0].Status != Failed || results[0].Message != "PLAN.md changed after Ada approved it" {
+		t.Fatalf("results: %+v", results)
+	}
+}
+
+func TestApprovalGateIgnoresScaffoldedSession(t *testing.T) {
+	projectRoot := t.TempDir()
+	write(t, projectRoot, "changes/001-alpha/PLAN.md", "# Plan\n")
+	write(t, projectRoot, "changes/001-alpha/TASKS.md", "- [ ] T1: start\n")
+	write(t, projectRoot, "changes/001-alpha/SESSION.md", "# Session Log: alpha\n\n## Completed (with commit refs)\n- [task description] → commit abc1234\n\n## In Progress\n- [task description]\n")
+
+	results, err := Evaluate(Input{ProjectRoot: projectRoot, Features: []string{"001-alpha"}, Gates: []int{GateApproval}})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if len(results) != 1 || results[0].Status != Skipped {
+		t.Fatalf("a scaffolded SESSION.md is not started work: %+v", results)
+	}
+}
+
+func TestVerificationGateOnlyAppliesToPullRequests(t *testing.T) {
+	projectRoot := t.TempDir()
+	write(t, projectRoot, "changes/001-alpha/VERIFICATION_REPORT.md", "## VERDICT\nNEEDS WORK\n")
+
+	results, err := Evaluate(Input{ProjectRoot: projectRoot, Features: []string{"001-alpha"}, Gates: []int{GateVerification}})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if len(results) != 1 || results[0].Status != Skipped {
+		t.Fatalf("outside a PR: %+v", results)
+	}
+
+	results, err = Evaluate(Input{ProjectRoot: projectRoot, Features: []string{"001-alpha"}, Selected: true, Gates: []int{GateVerification}, Changed: []string{"main.go"}})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if len(results) != 1 || results[0].Status != Failed || results[0].Message != "verdict is NEEDS WORK, want READY FOR PR before merge" {
+		t.Fatalf("in a PR: %+v", results)
+	}
+}
+
+func TestVerificationGateFailsUnattributedSourceChanges(t *testing.T) {
+	projectRoot := t.TempDir()
+	write(t, projectRoot, "changes/001-alpha/TASKS.md", "- [ ] T1: start\n")
+
+	results, err := Evaluate(Input{ProjectRoot: projectRoot, Features: []string{"001-alpha"}, Gates: []int{GateVerification}, Changed: []string{"README.md", "internal/alpha.go"}})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	last := results[len(results)-1]
+	if len(results) != 2 || last.Status != Failed || last.File != "internal/alpha.go" || !strings.Contains(last.Message, "--feature") {
+		t.Fatalf("results: %+v", results)
+	}
+
+	results, err = Evaluate(Input{ProjectRoot: projectRoot, Features: []string{"001-alpha"}, Gates: []int{GateVerification}, Changed: []string{"docs/guide.md"}})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if Failures(results) != 0 {
+		t.Fatalf("docs-only PR: %+v", results)
+	}
+}
+
+func write(t *testing.T, projectRoot string, name string, content string) {
+	t.Helper()
+	path := filepath.Join(projectRoot, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
@@ -0,0 +1,109 @@
+package gate
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Failures counts the failed results.
+func Failures(results []Result) int {
+	count := 0
+	for _, result := range results {
+		if result.Status == Failed {
+			count++
+		}
+	}
+	return count
+}
+
+// WriteText prints one line per result for people reading a terminal.
+func WriteText(w io.Writer, results []Result) {
+	for _, result := range results {
+		label := "ok  "
+		switch result.Status {
+		case Failed:
+			label = "FAIL"
+		case Skipped:
+			label = "skip"
+		}
+		fmt.Fprintf(w, "%s gate %d %s: %s\n", label, result.Gate, result.Feature, result.Message)
+	}
+}
+
+// WriteAnnotations prints failures as GitHub Actions workflow commands, which
+// show up on the offending file in the pull request.
+func WriteAnnotations(w io.Writer, results []Result) {
+	for _, result := range results {
+		if result.Status != Failed {
+			continue
+		}
+		title := fmt.Sprintf("Gate %d (%s)", result.Gate, result.Feature)
+		fmt.Fprintf(w, "::error file=%s,title=%s::%s\n", escapeProperty(result.File), escapeProperty(title), escapeData(result.Message))
+	}
+}
+
+type junitSuite struct {
+	XMLName  xml.Name    `xml:"testsuite"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+}
+
+// WriteJUnit writes the results as a JUnit XML test suite with one test case
+// per feature and gate.
+func WriteJUnit(w io.Writer, results []Result) error {
+	suite := junitSuite{Name: "spire gate check", Tests: len(results)}
+	for _, result := range results {
+		testCase := junitCase{
+			Name:      fmt.Sprintf("gate %d", result.Gate),
+			Classname: result.Feature,
+			File:      result.File,
+		}
+		switch result.Status {
+		case Failed:
+			suite.Failures++
+			testCase.Failure = &junitMessage{Message: result.Message}
+		case Skipped:
+			suite.Skipped++
+			testCase.Skipped = &junitMessage{Message: result.Message}
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("write junit report: %w", err)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suite); err != nil {
+		return fmt.Errorf("encode junit report: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("write junit report: %w", err)
+	}
+	return nil
+}
+
+func escapeData(value string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(value)
+}
+
+func escapeProperty(value string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(value)
+}
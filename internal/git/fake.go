@@ -0,0 +1,159 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Fake is an in-memory Repo for tests. Commits are keyed by full sha, Head
+// names the commit "HEAD" resolves to, Refs maps branch names to their tip
+// commits, and Settings holds git config values.
+type Fake struct {
+	Head     string
+	Refs     map[string]string
+	Commits  map[string]FakeCommit
+	Settings map[string]string
+}
+
+type FakeCommit struct {
+	Parents []string
+	Files   []string
+	Time    time.Time
+}
+
+func (f *Fake) ResolveCommit(ctx context.Context, ref string) (string, error) {
+	if ref == "HEAD" && f.Head != "" {
+		return f.Head, nil
+	}
+	if tip, ok := f.Refs[ref]; ok {
+		return tip, nil
+	}
+	if _, ok := f.Commits[ref]; ok {
+		return ref, nil
+	}
+
+	var matches []string
+	for sha := range f.Commits {
+		if len(ref) >= 4 && strings.HasPrefix(sha, strings.ToLower(ref)) {
+			matches = append(matches, sha)
+		}
+	}
+	if len(matches) != 1 {
+		return "", fmt.Errorf("%w %s", ErrUnknownCommit, ref)
+	}
+	return matches[0], nil
+}
+
+func (f *Fake) IsAncestor(ctx context.Context, commit string, descendant string) (bool, error) {
+	descendant, err := f.ResolveCommit(ctx, descendant)
+	if err != nil {
+		return false, err
+	}
+	commit, err = f.ResolveCommit(ctx, commit)
+	if err != nil {
+		return false, err
+	}
+
+	return f.reachable(descendant)[commit], nil
+}
+
+func (f *Fake) ChangedFiles(ctx context.Context, commit string) ([]string, error) {
+	entry, ok := f.Commits[commit]
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrUnknownCommit, commit)
+	}
+	return entry.Files, nil
+}
+
+func (f *Fake) DiffFiles(ctx context.Context, base string, head string) ([]string, error) {
+	base, err := f.ResolveCommit(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+	head, err = f.ResolveCommit(ctx, head)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := f.reachable(base)
+	seen := map[string]bool{}
+	var files []string
+	for sha := range f.reachable(head) {
+		if excluded[sha] {
+			continue
+		}
+		for _, file := range f.Commits[sha].Files {
+			if !seen[file] {
+				seen[file] = true
+				files = append(files, file)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func (f *Fake) Config(ctx context.Context, key string) (string, error) {
+	return f.Settings[key], nil
+}
+
+func (f *Fake) Branches(ctx context.Context) ([]string, error) {
+	names := make([]string, 0, len(f.Refs))
+	for name := range f.Refs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (f *Fake) CommitsAhead(ctx context.Context, branch string, base string) (int, error) {
+	branch, err := f.ResolveCommit(ctx, branch)
+	if err != nil {
+		return 0, err
+	}
+	base, err = f.ResolveCommit(ctx, base)
+	if err != nil {
+		return 0, err
+	}
+
+	excluded := f.reachable(base)
+	count := 0
+	for sha := range f.reachable(branch) {
+		if !excluded[sha] {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *Fake) LastCommitTime(ctx context.Context, path string) (time.Time, error) {
+	var last time.Time
+	for sha := range f.reachable(f.Head) {
+		entry := f.Commits[sha]
+		for _, file := range entry.Files {
+			if (file == path || strings.HasPrefix(file, strings.TrimSuffix(path, "/")+"/")) && entry.Time.After(last) {
+				last = entry.Time
+			}
+		}
+	}
+	return last, nil
+}
+
+// reachable returns every commit reachable from tip, tip included.
+func (f *Fake) reachable(tip string) map[string]bool {
+	seen := map[string]bool{}
+	pending := []string{tip}
+	for len(pending) > 0 {
+		sha := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if sha == "" || seen[sha] {
+			continue
+		}
+		seen[sha] = true
+		pending = append(pending, f.Commits[sha].Parents...)
+	}
+	return seen
+}
@@ -0,0 +1,171 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotRepository = errors.New("not a git repository")
+	ErrUnknownCommit = errors.New("unknown commit")
+)
+
+// Repo is the part of git that spire reads. CLI runs the git binary; Fake
+// stands in for it in tests.
+type Repo interface {
+	// ResolveCommit expands a full or abbreviated sha to the full commit id.
+	// It returns ErrUnknownCommit when no such commit exists.
+	ResolveCommit(ctx context.Context, ref string) (string, error)
+	// IsAncestor reports whether commit is reachable from descendant.
+	IsAncestor(ctx context.Context, commit string, descendant string) (bool, error)
+	// ChangedFiles lists the files commit touches, relative to the directory
+	// the repo was opened in.
+	ChangedFiles(ctx context.Context, commit string) ([]string, error)
+	// DiffFiles lists the files changed on head since it forked from base,
+	// relative to the directory the repo was opened in.
+	DiffFiles(ctx context.Context, base string, head string) ([]string, error)
+	// Config returns a git config value, or "" when it is not set.
+	Config(ctx context.Context, key string) (string, error)
+	// Branches lists local branch names.
+	Branches(ctx context.Context) ([]string, error)
+	// CommitsAhead counts the commits reachable from branch but not from base.
+	CommitsAhead(ctx context.Context, branch string, base string) (int, error)
+	// LastCommitTime is the committer time of the newest commit on HEAD that
+	// touches path, or the zero time when there is none.
+	LastCommitTime(ctx context.Context, path string) (time.Time, error)
+}
+
+type CLI struct {
+	dir string
+}
+
+// Open returns a CLI rooted at dir, or ErrNotRepository when dir is not inside
+// a git work tree or git is not installed.
+func Open(ctx context.Context, dir string) (*CLI, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("%w: git not found", ErrNotRepository)
+	}
+
+	repo := &CLI{dir: dir}
+	inside, err := repo.run(ctx, "rev-parse", "--is-inside-work-tree")
+	if err != nil || inside != "true" {
+		return nil, fmt.Errorf("%w: %s", ErrNotRepository, dir)
+	}
+	return repo, nil
+}
+
+func (c *CLI) ResolveCommit(ctx context.Context, ref string) (string, error) {
+	commit, err := c.run(ctx, "rev-parse", "--verify", "--quiet", "--end-of-options", ref+"^{commit}")
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("%w %s", ErrUnknownCommit, ref)
+		}
+		return "", err
+	}
+	return commit, nil
+}
+
+func (c *CLI) IsAncestor(ctx context.Context, commit string, descendant string) (bool, error) {
+	_, err := c.run(ctx, "merge-base", "--is-ancestor", commit, descendant)
+	if err == nil {
+		return true, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, err
+}
+
+func (c *CLI) ChangedFiles(ctx context.Context, commit string) ([]string, error) {
+	output, err := c.run(ctx, "diff-tree", "--no-commit-id", "--name-only", "--relative", "-r", "--root", commit)
+	if err != nil {
+		return nil, err
+	}
+	return splitLines(output), nil
+}
+
+func (c *CLI) DiffFiles(ctx context.Context, base string, head string) ([]string, error) {
+	output, err := c.run(ctx, "diff", "--name-only", "--relative", base+"..."+head)
+	if err != nil {
+		return nil, err
+	}
+	return splitLines(output), nil
+}
+
+func (c *CLI) Config(ctx context.Context, key string) (string, error) {
+	value, err := c.run(ctx, "config", "--get", key)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", err
+	}
+	return value, nil
+}
+
+func (c *CLI) Branches(ctx context.Context) ([]string, error) {
+	output, err := c.run(ctx, "for-each-ref", "--format=%(refname:short)", "refs/heads")
+	if err != nil {
+		return nil, err
+	}
+	return splitLines(output), nil
+}
+
+func (c *CLI) CommitsAhead(ctx context.Context, branch string, base string) (int, error) {
+	output, err := c.run(ctx, "rev-list", "--count", base+".."+branch)
+	if err != nil {
+		return 0, err
+	}
+	count, err := strconv.Atoi(output)
+	if err != nil {
+		return 0, fmt.Errorf("parse commit count %q: %w", output, err)
+	}
+	return count, nil
+}
+
+func (c *CLI) LastCommitTime(ctx context.Context, path string) (time.Time, error) {
+	output, err := c.run(ctx, "log", "-1", "--format=%cI", "--", path)
+	if err != nil || output == "" {
+		return time.Time{}, err
+	}
+	when, err := time.Parse(time.RFC3339, output)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse commit time %q: %w", output, err)
+	}
+	return when, nil
+}
+
+func (c *CLI) run(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = c.dir
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("git %s: %s: %w", args[0], message, err)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func splitLines(output string) []string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
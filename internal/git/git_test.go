@@ -0,0 +1,110 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestCLIReadsCommitsFromRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	gitRun(t, dir, "init", "-q")
+	first := commitFile(t, dir, "a.go", "first")
+	gitRun(t, dir, "checkout", "-q", "-b", "side")
+	side := commitFile(t, dir, "b.go", "side")
+	gitRun(t, dir, "checkout", "-q", "-")
+
+	ctx := context.Background()
+	repo, err := Open(ctx, dir)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	resolved, err := repo.ResolveCommit(ctx, first[:7])
+	if err != nil || resolved != first {
+		t.Fatalf("ResolveCommit: got %q, %v; want %q", resolved, err, first)
+	}
+	if _, err := repo.ResolveCommit(ctx, "deadbeef"); !errors.Is(err, ErrUnknownCommit) {
+		t.Fatalf("ResolveCommit unknown: got %v", err)
+	}
+	if ok, err := repo.IsAncestor(ctx, first, "HEAD"); err != nil || !ok {
+		t.Fatalf("IsAncestor first: %v, %v", ok, err)
+	}
+	if ok, err := repo.IsAncestor(ctx, side, "HEAD"); err != nil || ok {
+		t.Fatalf("IsAncestor side: %v, %v", ok, err)
+	}
+	if files, err := repo.ChangedFiles(ctx, side); err != nil || len(files) != 1 || files[0] != "b.go" {
+		t.Fatalf("ChangedFiles: %v, %v", files, err)
+	}
+
+	if files, err := repo.DiffFiles(ctx, "HEAD", "side"); err != nil || len(files) != 1 || files[0] != "b.go" {
+		t.Fatalf("DiffFiles: %v, %v", files, err)
+	}
+	if branches, err := repo.Branches(ctx); err != nil || len(branches) != 2 {
+		t.Fatalf("Branches: %v, %v", branches, err)
+	}
+	if ahead, err := repo.CommitsAhead(ctx, "side", "HEAD"); err != nil || ahead != 1 {
+		t.Fatalf("CommitsAhead: %d, %v", ahead, err)
+	}
+	if when, err := repo.LastCommitTime(ctx, "a.go"); err != nil || when.IsZero() {
+		t.Fatalf("LastCommitTime a.go: %v, %v", when, err)
+	}
+	if when, err := repo.LastCommitTime(ctx, "b.go"); err != nil || !when.IsZero() {
+		t.Fatalf("LastCommitTime b.go should be off HEAD: %v, %v", when, err)
+	}
+
+	gitRun(t, dir, "config", "user.name", "Ada Lovelace")
+	if name, err := repo.Config(ctx, "user.name"); err != nil || name != "Ada Lovelace" {
+		t.Fatalf("Config user.name: %q, %v", name, err)
+	}
+	if value, err := repo.Config(ctx, "spire.unset"); err != nil || value != "" {
+		t.Fatalf("Config unset: %q, %v", value, err)
+	}
+
+	if _, err := Open(ctx, t.TempDir()); !errors.Is(err, ErrNotRepository) {
+		t.Fatalf("Open outside repo: got %v", err)
+	}
+}
+
+func TestFakeFollowsParents(t *testing.T) {
+	fake := &Fake{Head: "cccc333", Commits: map[string]FakeCommit{
+		"aaaa111": {},
+		"bbbb222": {Parents: []string{"aaaa111"}},
+		"cccc333": {Parents: []string{"bbbb222"}},
+		"dddd444": {Parents: []string{"aaaa111"}},
+	}}
+
+	ctx := context.Background()
+	if ok, _ := fake.IsAncestor(ctx, "aaaa", "HEAD"); !ok {
+		t.Fatal("aaaa111 should be reachable from HEAD")
+	}
+	if ok, _ := fake.IsAncestor(ctx, "dddd444", "HEAD"); ok {
+		t.Fatal("dddd444 should not be reachable from HEAD")
+	}
+}
+
+func commitFile(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	gitRun(t, dir, "add", name)
+	gitRun(t, dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", name)
+	return gitRun(t, dir, "rev-parse", "HEAD")
+}
+
+func gitRun(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	output, err := (&CLI{dir: dir}).run(context.Background(), args...)
+	if err != nil {
+		t.Fatalf("git %v: %v", args, err)
+	}
+	return output
+}
@@ -0,0 +1,305 @@
+package methodology
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"opencode-spire/internal/download"
+)
+
+const (
+	cacheDirEnv       = "SPIRE_CACHE_DIR"
+	cacheEntryFile    = "entry.json"
+	cachePayloadDir   = "methodology"
+	cacheTempPrefix   = ".tmp-"
+	cacheNamespaceDir = "methodology"
+)
+
+var ErrNoCachedPayload = errors.New("no cached methodology payload")
+
+type FetchOptions struct {
+	Offline  bool
+	Timeout  time.Duration
+	Progress io.Writer
+	// Warnings receives notes about cache problems that did not stop the fetch.
+	Warnings io.Writer
+}
+
+type CacheEntry struct {
+	Repository string `json:"repository"`
+	Ref        string `json:"ref"`
+	Digest     string `json:"digest"`
+	ETag       string `json:"etag,omitempty"`
+	TarballURL string `json:"tarball_url"`
+	FetchedAt  string `json:"fetched_at"`
+	Dir        string `json:"-"`
+}
+
+func (e CacheEntry) PayloadDir() string {
+	return filepath.Join(e.Dir, cachePayloadDir)
+}
+
+func CacheDir() (string, error) {
+	if dir := os.Getenv(cacheDirEnv); dir != "" {
+		return dir, nil
+	}
+
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user cache dir: %w", err)
+	}
+	return filepath.Join(base, "spire"), nil
+}
+
+// ListCacheEntries lists cached payloads, newest first per repository and ref.
+// Entries with an unreadable entry.json are skipped with a note to warn.
+func ListCacheEntries(warn io.Writer) ([]CacheEntry, error) {
+	root, err := CacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return listCacheEntries(root, warn)
+}
+
+// PruneCache removes every cached payload except the newest one per
+// repository and ref, or all of them when all is set.
+func PruneCache(all bool, warn io.Writer) ([]CacheEntry, error) {
+	entries, err := ListCacheEntries(warn)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := map[string]bool{}
+	var removed []CacheEntry
+	for _, entry := range entries {
+		key := entry.Repository + "@" + entry.Ref
+		if !all && !kept[key] {
+			kept[key] = true
+			continue
+		}
+
+		if err := os.RemoveAll(entry.Dir); err != nil {
+			return removed, fmt.Errorf("remove cached payload %q: %w", entry.Dir, err)
+		}
+		removed = append(removed, entry)
+	}
+
+	return removed, nil
+}
+
+func listCacheEntries(root string, warn io.Writer) ([]CacheEntry, error) {
+	matches, err := filepath.Glob(filepath.Join(root, cacheNamespaceDir, "*", "*", "*", cacheEntryFile))
+	if err != nil {
+		return nil, fmt.Errorf("scan cache: %w", err)
+	}
+
+	entries := make([]CacheEntry, 0, len(matches))
+	for _, match := range matches {
+		entry, err := readCacheEntry(filepath.Dir(match))
+		if err != nil {
+			if warn != nil {
+				fmt.Fprintf(warn, "warning: skipping cached payload: %v\n", err)
+			}
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Repository != entries[j].Repository {
+			return entries[i].Repository < entries[j].Repository
+		}
+		if entries[i].Ref != entries[j].Ref {
+			return entries[i].Ref < entries[j].Ref
+		}
+		return cacheTime(entries[i]).After(cacheTime(entries[j]))
+	})
+
+	return entries, nil
+}
+
+func cacheTime(entry CacheEntry) time.Time {
+	parsed, err := time.Parse(time.RFC3339Nano, entry.FetchedAt)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed
+}
+
+func newestCacheEntry(root string, repository string, ref string, warn io.Writer) (*CacheEntry, error) {
+	entries, err := listCacheEntries(root, warn)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.Repository == repository && entry.Ref == ref {
+			return &entry, nil
+		}
+	}
+	return nil, nil
+}
+
+func readCacheEntry(dir string) (CacheEntry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, cacheEntryFile))
+	if err != nil {
+		return CacheEntry{}, fmt.Errorf("read cache entry: %w", err)
+	}
+
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return CacheEntry{}, fmt.Errorf("parse cache entry %q: %w", dir, err)
+	}
+	entry.Dir = dir
+
+	return entry, nil
+}
+
+func writeCacheEntry(entry CacheEntry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("serialize cache entry: %w", err)
+	}
+
+	// Write and rename so a reader never sees a half-written entry.
+	temp, err := os.CreateTemp(entry.Dir, cacheTempPrefix+cacheEntryFile+"-*")
+	if err != nil {
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		_ = os.Remove(temp.Name())
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		_ = os.Remove(temp.Name())
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	if err := os.Chmod(temp.Name(), 0o644); err != nil {
+		_ = os.Remove(temp.Name())
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	if err := os.Rename(temp.Name(), filepath.Join(entry.Dir, cacheEntryFile)); err != nil {
+		_ = os.Remove(temp.Name())
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+
+	return nil
+}
+
+// usableCacheDir creates root if needed and checks that it can hold files.
+func usableCacheDir(root string) error {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+	probe, err := os.CreateTemp(root, cacheTempPrefix+"probe-*")
+	if err != nil {
+		return fmt.Errorf("write to cache dir: %w", err)
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+func cacheEntryDir(root string, repository string, ref string, digest string) string {
+	return filepath.Join(root, cacheNamespaceDir, url.PathEscape(repository), url.PathEscape(ref), digest)
+}
+
+func offlineSource(root string, metadata SourceMetadata, warn io.Writer) (string, string, func(), error) {
+	entry, err := newestCacheEntry(root, metadata.Repository, metadata.Ref, warn)
+	if err != nil {
+		return "", "", nil, err
+	}
+	if entry == nil {
+		return "", "", nil, fmt.Errorf("%w for %s@%s", ErrNoCachedPayload, metadata.Repository, metadata.Ref)
+	}
+
+	return entry.PayloadDir(), entry.Digest, func() {}, nil
+}
+
+// fetchIntoCache downloads the tarball, revalidating the newest cached copy
+// with If-None-Match, and stores new payloads under their sha256 digest.
+func fetchIntoCache(ctx context.Context, client *download.Client, root string, metadata SourceMetadata, warn io.Writer) (string, string, func(), error) {
+	previous, err := newestCacheEntry(root, metadata.Repository, metadata.Ref, warn)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	etag := ""
+	if previous != nil {
+		etag = previous.ETag
+	}
+
+	tarball, resp, err := downloadTarball(ctx, client, root, metadata.TarballURL, etag)
+	if err != nil {
+		return "", "", nil, err
+	}
+	defer os.Remove(tarball.Name())
+	defer tarball.Close()
+
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+
+	if resp.StatusCode == http.StatusNotModified {
+		previous.FetchedAt = now
+		if err := writeCacheEntry(*previous); err != nil {
+			return "", "", nil, err
+		}
+		return previous.PayloadDir(), previous.Digest, func() {}, nil
+	}
+
+	digest, err := fileDigest(tarball)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	entry := CacheEntry{
+		Repository: metadata.Repository,
+		Ref:        metadata.Ref,
+		Digest:     digest,
+		ETag:       resp.Header.Get("ETag"),
+		TarballURL: metadata.TarballURL,
+		FetchedAt:  now,
+		Dir:        cacheEntryDir(root, metadata.Repository, metadata.Ref, digest),
+	}
+
+	if _, err := os.Stat(filepath.Join(entry.Dir, cacheEntryFile)); err == nil {
+		if err := writeCacheEntry(entry); err != nil {
+			return "", "", nil, err
+		}
+		return entry.PayloadDir(), digest, func() {}, nil
+	}
+
+	staging, err := os.MkdirTemp(root, cacheTempPrefix+"*")
+	if err != nil {
+		return "", "", nil, fmt.Errorf("create cache staging dir: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	if err := extractMethodologySubtree(ctx, tarball, filepath.Join(staging, cachePayloadDir)); err != nil {
+		return "", "", nil, err
+	}
+	if err := verifyPayload(filepath.Join(staging, cachePayloadDir)); err != nil {
+		return "", "", nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(entry.Dir), 0o755); err != nil {
+		return "", "", nil, fmt.Errorf("create cache entry dir: %w", err)
+	}
+	_ = os.RemoveAll(entry.Dir)
+	if err := os.Rename(staging, entry.Dir); err != nil {
+		return "", "", nil, fmt.Errorf("store cached payload: %w", err)
+	}
+	if err := writeCacheEntry(entry); err != nil {
+		return "", "", nil, err
+	}
+
+	return entry.PayloadDir(), digest, func() {}, nil
+}
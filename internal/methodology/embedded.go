@@ -0,0 +1,104 @@
+package methodology
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	spire "opencode-spire"
+)
+
+var (
+	embeddedPayload = spire.Methodology
+	embeddedVersion = spire.PayloadVersion
+)
+
+func EmbeddedSourceMetadata() SourceMetadata {
+	return SourceMetadata{
+		Repository: canonicalRepository,
+		Ref:        canonicalRef,
+		Embedded:   true,
+		Version:    embeddedVersion,
+	}
+}
+
+func EmbeddedPayloadVersion() string {
+	return embeddedVersion
+}
+
+// OnlineSource returns the metadata to fetch from once a project that was
+// initialized from the embedded payload can reach the network again.
+func (m SourceMetadata) OnlineSource() SourceMetadata {
+	return SourceMetadata{Repository: m.Repository, Ref: m.Ref}
+}
+
+func SetEmbeddedPayloadForTesting(payload fs.FS, version string) func() {
+	prevPayload := embeddedPayload
+	prevVersion := embeddedVersion
+
+	embeddedPayload = func() (fs.FS, error) { return payload, nil }
+	embeddedVersion = version
+
+	return func() {
+		embeddedPayload = prevPayload
+		embeddedVersion = prevVersion
+	}
+}
+
+func materializeEmbedded() (string, string, func(), error) {
+	payload, err := embeddedPayload()
+	if err != nil {
+		return "", "", nil, fmt.Errorf("open embedded payload: %w", err)
+	}
+
+	tempDir, err := os.MkdirTemp("", "spire-embedded-*")
+	if err != nil {
+		return "", "", nil, fmt.Errorf("create temp dir: %w", err)
+	}
+	cleanup := func() { _ = os.RemoveAll(tempDir) }
+
+	destination := filepath.Join(tempDir, "methodology")
+	if err := os.CopyFS(destination, payload); err != nil {
+		cleanup()
+		return "", "", nil, fmt.Errorf("extract embedded payload: %w", err)
+	}
+
+	if err := verifyPayload(destination); err != nil {
+		cleanup()
+		return "", "", nil, err
+	}
+
+	digest, err := payloadDigest(payload)
+	if err != nil {
+		cleanup()
+		return "", "", nil, err
+	}
+
+	return destination, digest, cleanup, nil
+}
+
+func payloadDigest(payload fs.FS) (string, error) {
+	hash := sha256.New()
+	err := fs.WalkDir(payload, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		content, err := fs.ReadFile(payload, path)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(hash, "%s\x00%d\x00", path, len(content))
+		hash.Write(content)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("hash embedded payload: %w", err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -3,6 +3,9 @@ package methodology
 import (
 	"archive/tar"
 	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,6 +14,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"opencode-spire/internal/download"
 )
 
 const (
@@ -20,7 +25,6 @@ const (
 )
 
 var (
-	httpClient          = &http.Client{Timeout: 30 * time.Second}
 	canonicalRepository = defaultSourceRepository
 	canonicalRef        = defaultSourceRef
 	canonicalTarballURL = ""
@@ -31,6 +35,9 @@ type SourceMetadata struct {
 	Ref        string `json:"ref"`
 	TarballURL string `json:"tarball_url"`
 	FetchedAt  string `json:"fetched_at"`
+	Digest     string `json:"digest,omitempty"`
+	Embedded   bool   `json:"embedded,omitempty"`
+	Version    string `json:"version,omitempty"`
 }
 
 func DefaultSourceMetadata() SourceMetadata {
@@ -41,24 +48,20 @@ func DefaultSourceMetadata() SourceMetadata {
 	}
 }
 
-func SyncCanonicalToProject(projectRoot string) (string, SourceMetadata, error) {
-	meta := DefaultSourceMetadata()
-	return syncSourceToProject(meta, projectRoot)
-}
-
-func SyncAndReportChangesFromMetadata(localDir string, metadata SourceMetadata) ([]string, SourceMetadata, error) {
+func SyncAndReportChangesFromMetadata(ctx context.Context, localDir string, metadata SourceMetadata, opts FetchOptions) ([]string, SourceMetadata, error) {
 	meta, err := normalizeSourceMetadata(metadata)
 	if err != nil {
 		return nil, SourceMetadata{}, err
 	}
 
-	sourceDir, cleanup, err := materializeSource(meta)
+	sourceDir, digest, cleanup, err := materializeSource(ctx, meta, opts)
 	if err != nil {
 		return nil, SourceMetadata{}, err
 	}
 	defer cleanup()
+	meta.Digest = digest
 
-	changedFiles, err := SyncAndReportChanges(localDir, sourceDir)
+	changedFiles, err := SyncAndReportChanges(ctx, localDir, sourceDir)
 	if err != nil {
 		return nil, SourceMetadata{}, err
 	}
@@ -127,19 +130,20 @@ func writeSourceMetadata(localDir string, metadata SourceMetadata) error {
 	return nil
 }
 
-func syncSourceToProject(metadata SourceMetadata, projectRoot string) (string, SourceMetadata, error) {
+func SyncSourceToProject(ctx context.Context, metadata SourceMetadata, projectRoot string, opts FetchOptions) (string, SourceMetadata, error) {
 	meta, err := normalizeSourceMetadata(metadata)
 	if err != nil {
 		return "", SourceMetadata{}, err
 	}
 
-	sourceDir, cleanup, err := materializeSource(meta)
+	sourceDir, digest, cleanup, err := materializeSource(ctx, meta, opts)
 	if err != nil {
 		return "", SourceMetadata{}, err
 	}
 	defer cleanup()
+	meta.Digest = digest
 
-	destination, err := SyncToProject(sourceDir, projectRoot)
+	destination, err := SyncToProject(ctx, sourceDir, projectRoot)
 	if err != nil {
 		return "", SourceMetadata{}, err
 	}
@@ -164,7 +168,7 @@ func normalizeSourceMetadata(metadata SourceMetadata) (SourceMetadata, error) {
 	}
 
 	tarballURL := strings.TrimSpace(metadata.TarballURL)
-	if tarballURL == "" {
+	if tarballURL == "" && !metadata.Embedded {
 		tarballURL = tarballURLFor(repository, ref)
 	}
 
@@ -173,6 +177,9 @@ func normalizeSourceMetadata(metadata SourceMetadata) (SourceMetadata, error) {
 		Ref:        ref,
 		TarballURL: tarballURL,
 		FetchedAt:  metadata.FetchedAt,
+		Digest:     metadata.Digest,
+		Embedded:   metadata.Embedded,
+		Version:    metadata.Version,
 	}, nil
 }
 
@@ -187,45 +194,124 @@ func tarballURLFor(repository string, ref string) string {
 	return fmt.Sprintf("https://github.com/%s/archive/refs/heads/%s.tar.gz", repository, ref)
 }
 
-func materializeSource(metadata SourceMetadata) (string, func(), error) {
-	resp, err := httpClient.Get(metadata.TarballURL)
-	if err != nil {
-		return "", nil, fmt.Errorf("download methodology tarball: %w", err)
+func materializeSource(ctx context.Context, metadata SourceMetadata, opts FetchOptions) (string, string, func(), error) {
+	if metadata.Embedded {
+		return materializeEmbedded()
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		resp.Body.Close()
-		return "", nil, fmt.Errorf("download methodology tarball: unexpected status %s", resp.Status)
+	root, err := CacheDir()
+	if err != nil && opts.Offline {
+		return "", "", nil, err
+	}
+	if opts.Offline {
+		return offlineSource(root, metadata, opts.Warnings)
 	}
 
+	client := download.New(download.Options{Timeout: opts.Timeout, Progress: opts.Progress})
+	if err == nil {
+		err = usableCacheDir(root)
+	}
+	if err != nil {
+		if opts.Warnings != nil {
+			fmt.Fprintf(opts.Warnings, "warning: downloading without the cache: %v\n", err)
+		}
+		return downloadToTemp(ctx, client, metadata)
+	}
+	return fetchIntoCache(ctx, client, root, metadata, opts.Warnings)
+}
+
+func downloadToTemp(ctx context.Context, client *download.Client, metadata SourceMetadata) (string, string, func(), error) {
 	tempDir, err := os.MkdirTemp("", "spire-methodology-*")
 	if err != nil {
-		resp.Body.Close()
-		return "", nil, fmt.Errorf("create temp dir: %w", err)
+		return "", "", nil, fmt.Errorf("create temp dir: %w", err)
 	}
+	cleanup := func() { _ = os.RemoveAll(tempDir) }
 
-	cleanup := func() {
-		resp.Body.Close()
-		_ = os.RemoveAll(tempDir)
+	tarball, _, err := downloadTarball(ctx, client, tempDir, metadata.TarballURL, "")
+	if err != nil {
+		cleanup()
+		return "", "", nil, err
+	}
+	defer tarball.Close()
+
+	digest, err := fileDigest(tarball)
+	if err != nil {
+		cleanup()
+		return "", "", nil, err
 	}
 
-	if err := extractMethodologySubtree(resp.Body, tempDir); err != nil {
+	payloadDir := filepath.Join(tempDir, "methodology")
+	if err := extractMethodologySubtree(ctx, tarball, payloadDir); err != nil {
 		cleanup()
-		return "", nil, err
+		return "", "", nil, err
 	}
 
-	if _, err := os.Stat(filepath.Join(tempDir, "project_root", "manifest.json")); err != nil {
+	if err := verifyPayload(payloadDir); err != nil {
 		cleanup()
+		return "", "", nil, err
+	}
+
+	return payloadDir, digest, cleanup, nil
+}
+
+// downloadTarball saves the tarball at url into a temp file under dir. With an
+// etag the request is conditional; a 304 is only returned in that case. The
+// caller owns the returned file and must remove it.
+func downloadTarball(ctx context.Context, client *download.Client, dir string, url string, etag string) (*os.File, *download.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("download methodology tarball: %w", err)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	tarball, err := os.CreateTemp(dir, cacheTempPrefix+"*.tar.gz")
+	if err != nil {
+		return nil, nil, fmt.Errorf("create temp file: %w", err)
+	}
+
+	resp, err := client.ToFile(ctx, req, tarball, "downloading methodology")
+	if err == nil && resp.StatusCode == http.StatusNotModified && etag == "" {
+		err = fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if err != nil {
+		tarball.Close()
+		_ = os.Remove(tarball.Name())
+		return nil, nil, fmt.Errorf("download methodology tarball: %w", err)
+	}
+
+	if _, err := tarball.Seek(0, io.SeekStart); err != nil {
+		tarball.Close()
+		_ = os.Remove(tarball.Name())
+		return nil, nil, fmt.Errorf("rewind methodology tarball: %w", err)
+	}
+
+	return tarball, resp, nil
+}
+
+func fileDigest(file *os.File) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("hash %q: %w", file.Name(), err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("rewind %q: %w", file.Name(), err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func verifyPayload(dir string) error {
+	if _, err := os.Stat(filepath.Join(dir, "project_root", "manifest.json")); err != nil {
 		if os.IsNotExist(err) {
-			return "", nil, fmt.Errorf("methodology payload missing project_root/manifest.json")
+			return fmt.Errorf("methodology payload missing project_root/manifest.json")
 		}
-		return "", nil, fmt.Errorf("verify extracted payload: %w", err)
+		return fmt.Errorf("verify extracted payload: %w", err)
 	}
-
-	return tempDir, cleanup, nil
+	return nil
 }
 
-func extractMethodologySubtree(tarGz io.Reader, destination string) error {
+func extractMethodologySubtree(ctx context.Context, tarGz io.Reader, destination string) error {
 	gzipReader, err := gzip.NewReader(tarGz)
 	if err != nil {
 		return fmt.Errorf("open tarball stream: %w", err)
@@ -236,6 +322,10 @@ func extractMethodologySubtree(tarGz io.Reader, destination string) error {
 	var extracted bool
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("extract methodology payload: %w", err)
+		}
+
 		header, err := tarReader.Next()
 		if err == io.EOF {
 			break
@@ -304,25 +394,72 @@ func extractMethodologySubtree(tarGz io.Reader, destination string) error {
 	return nil
 }
 
-func SyncToProject(sourceDir string, projectRoot string) (string, error) {
+func SyncToProject(ctx context.Context, sourceDir string, projectRoot string) (string, error) {
 	destination := filepath.Join(projectRoot, ".methodology")
-	if err := copyDir(sourceDir, destination); err != nil {
+	err := replaceDir(destination, func(staging string) error {
+		if err := copyDir(ctx, sourceDir, staging); err != nil {
+			return err
+		}
+
+		hashes, err := dirFileHashes(staging)
+		if err != nil {
+			return err
+		}
+
+		return writeSyncState(staging, hashes)
+	})
+	if err != nil {
 		return "", err
 	}
 
-	hashes, err := dirFileHashes(destination)
+	return destination, nil
+}
+
+// replaceDir builds the new contents of destination in a sibling staging
+// directory and swaps it in with renames, so an interrupted or failed build
+// leaves destination exactly as it was.
+func replaceDir(destination string, build func(staging string) error) error {
+	staging, err := os.MkdirTemp(filepath.Dir(destination), filepath.Base(destination)+".staging-*")
 	if err != nil {
-		return "", err
+		return fmt.Errorf("create staging directory: %w", err)
 	}
+	defer os.RemoveAll(staging)
 
-	if err := writeSyncState(destination, hashes); err != nil {
-		return "", err
+	if err := os.Chmod(staging, 0o755); err != nil {
+		return fmt.Errorf("chmod staging directory %q: %w", staging, err)
 	}
 
-	return destination, nil
+	if err := build(staging); err != nil {
+		return err
+	}
+
+	backup := destination + ".previous"
+	if err := os.RemoveAll(backup); err != nil {
+		return fmt.Errorf("remove stale backup %q: %w", backup, err)
+	}
+
+	existed := true
+	if err := os.Rename(destination, backup); err != nil {
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("move aside %q: %w", destination, err)
+		}
+		existed = false
+	}
+
+	if err := os.Rename(staging, destination); err != nil {
+		if existed {
+			_ = os.Rename(backup, destination)
+		}
+		return fmt.Errorf("move staging directory into %q: %w", destination, err)
+	}
+
+	if existed {
+		_ = os.RemoveAll(backup)
+	}
+	return nil
 }
 
-func copyDir(src string, dst string) error {
+func copyDir(ctx context.Context, src string, dst string) error {
 	entries, err := os.ReadDir(src)
 	if err != nil {
 		return fmt.Errorf("read source directory %q: %w", src, err)
@@ -333,11 +470,15 @@ func copyDir(src string, dst string) error {
 	}
 
 	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("copy %q: %w", src, err)
+		}
+
 		srcPath := filepath.Join(src, entry.Name())
 		dstPath := filepath.Join(dst, entry.Name())
 
 		if entry.IsDir() {
-			if err := copyDir(srcPath, dstPath); err != nil {
+			if err := copyDir(ctx, srcPath, dstPath); err != nil {
 				return err
 			}
 			continue
@@ -379,3 +520,48 @@ func copyFile(src string, dst string) error {
 
 	return nil
 }
+
+// Checkpoint is a copy of a .methodology directory taken before an update, so
+// a later step that fails can put the previous payload back.
+type Checkpoint struct {
+	dir   string
+	saved string
+}
+
+// NewCheckpoint copies localDir into a sibling directory.
+func NewCheckpoint(ctx context.Context, localDir string) (*Checkpoint, error) {
+	saved, err := os.MkdirTemp(filepath.Dir(localDir), filepath.Base(localDir)+".checkpoint-*")
+	if err != nil {
+		return nil, fmt.Errorf("create checkpoint directory: %w", err)
+	}
+	if err := os.Chmod(saved, 0o755); err != nil {
+		_ = os.RemoveAll(saved)
+		return nil, fmt.Errorf("chmod checkpoint directory %q: %w", saved, err)
+	}
+	if err := copyDir(ctx, localDir, saved); err != nil {
+		_ = os.RemoveAll(saved)
+		return nil, err
+	}
+	return &Checkpoint{dir: localDir, saved: saved}, nil
+}
+
+// Restore swaps the saved copy back into place.
+func (c *Checkpoint) Restore() error {
+	current := c.dir + ".failed"
+	if err := os.RemoveAll(current); err != nil {
+		return fmt.Errorf("remove stale %q: %w", current, err)
+	}
+	if err := os.Rename(c.dir, current); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("move aside %q: %w", c.dir, err)
+	}
+	if err := os.Rename(c.saved, c.dir); err != nil {
+		_ = os.Rename(current, c.dir)
+		return fmt.Errorf("restore %q: %w", c.dir, err)
+	}
+	return os.RemoveAll(current)
+}
+
+// Discard removes the saved copy once the update has been kept.
+func (c *Checkpoint) Discard() {
+	_ = os.RemoveAll(c.saved)
+}
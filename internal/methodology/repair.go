@@ -0,0 +1,137 @@
+package methodology
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+var ErrNoSyncState = errors.New("no sync state recorded")
+
+type RepairReport struct {
+	Restored []string
+	// Unavailable lists missing files whose recorded content neither the
+	// cached payload nor the current upstream still has.
+	Unavailable []string
+}
+
+// RepairFromMetadata restores files the sync state lists but that are missing
+// from localDir. Files that still exist are left alone, even when edited. A
+// file is only restored with exactly the content the sync state recorded, taken
+// from the cached payload for the recorded digest when there is one, so the
+// result never mixes two payload versions.
+func RepairFromMetadata(ctx context.Context, localDir string, metadata SourceMetadata, opts FetchOptions) (RepairReport, error) {
+	state, err := readSyncState(localDir)
+	if err != nil {
+		return RepairReport{}, err
+	}
+	if state == nil || len(state.Hashes) == 0 {
+		return RepairReport{}, ErrNoSyncState
+	}
+
+	var missing []string
+	for path := range state.Hashes {
+		if _, err := os.Stat(filepath.Join(localDir, filepath.FromSlash(path))); err != nil {
+			if !os.IsNotExist(err) {
+				return RepairReport{}, fmt.Errorf("stat %q: %w", path, err)
+			}
+			missing = append(missing, path)
+		}
+	}
+	sort.Strings(missing)
+
+	if len(missing) == 0 {
+		return RepairReport{}, nil
+	}
+
+	meta, err := normalizeSourceMetadata(metadata)
+	if err != nil {
+		return RepairReport{}, err
+	}
+
+	sourceDir, cleanup, err := recordedPayload(ctx, meta, opts)
+	if err != nil {
+		return RepairReport{}, err
+	}
+	defer cleanup()
+
+	var report RepairReport
+	for _, path := range missing {
+		source := filepath.Join(sourceDir, filepath.FromSlash(path))
+		content, err := os.ReadFile(source)
+		if err != nil && !os.IsNotExist(err) {
+			return RepairReport{}, fmt.Errorf("read source file %q: %w", source, err)
+		}
+
+		sum := sha256.Sum256(content)
+		if err != nil || hex.EncodeToString(sum[:]) != state.Hashes[path] {
+			report.Unavailable = append(report.Unavailable, path)
+			continue
+		}
+
+		if err := copyFile(source, filepath.Join(localDir, filepath.FromSlash(path))); err != nil {
+			return RepairReport{}, err
+		}
+		report.Restored = append(report.Restored, path)
+	}
+
+	return report, nil
+}
+
+// recordedPayload returns the cached payload for the recorded digest, or
+// fetches the source afresh when it is not cached.
+func recordedPayload(ctx context.Context, meta SourceMetadata, opts FetchOptions) (string, func(), error) {
+	if meta.Digest != "" && !meta.Embedded {
+		if root, err := CacheDir(); err == nil {
+			entry, err := readCacheEntry(cacheEntryDir(root, meta.Repository, meta.Ref, meta.Digest))
+			if err == nil && entry.Digest == meta.Digest {
+				return entry.PayloadDir(), func() {}, nil
+			}
+		}
+	}
+
+	sourceDir, _, cleanup, err := materializeSource(ctx, meta, opts)
+	if err != nil {
+		return "", nil, err
+	}
+	return sourceDir, cleanup, nil
+}
+
+// DiscardUnreadableState removes the sync state, source metadata and project
+// settings in localDir that cannot be read, so a reinit can write them afresh.
+// It returns why each removed file was unreadable.
+func DiscardUnreadableState(localDir string) ([]error, error) {
+	checks := []struct {
+		path string
+		read func() error
+	}{
+		{syncStatePath(localDir), func() error { _, err := readSyncState(localDir); return err }},
+		{sourceMetadataPath(localDir), func() error { _, err := ReadSourceMetadata(localDir); return err }},
+		{projectSettingsPath(localDir), func() error {
+			_, err := readProjectSettingsFile(projectSettingsPath(localDir))
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}},
+	}
+
+	var discarded []error
+	for _, check := range checks {
+		readErr := check.read()
+		if readErr == nil {
+			continue
+		}
+		if err := os.Remove(check.path); err != nil && !os.IsNotExist(err) {
+			return discarded, fmt.Errorf("remove %q: %w", check.path, err)
+		}
+		discarded = append(discarded, fmt.Errorf("%s: %w", filepath.Base(check.path), readErr))
+	}
+
+	return discarded, nil
+}
@@ -0,0 +1,74 @@
+package methodology
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ProjectSettingsFilename is the committed file at the project root that
+// holds the profile and targets, so every clone projects the same files.
+const ProjectSettingsFilename = ".spire.json"
+
+// legacyProjectSettingsFilename is where settings lived inside .methodology,
+// which is gitignored. It is still read until init writes the root file.
+const legacyProjectSettingsFilename = ".spire-project.json"
+
+type ProjectSettings struct {
+	Profile string   `json:"profile,omitempty"`
+	Targets []string `json:"targets,omitempty"`
+}
+
+func ReadProjectSettings(projectRoot string) (ProjectSettings, error) {
+	settings, err := readProjectSettingsFile(filepath.Join(projectRoot, ProjectSettingsFilename))
+	if os.IsNotExist(err) {
+		settings, err = readProjectSettingsFile(projectSettingsPath(filepath.Join(projectRoot, ".methodology")))
+	}
+	if os.IsNotExist(err) {
+		return ProjectSettings{}, nil
+	}
+	return settings, err
+}
+
+func readProjectSettingsFile(path string) (ProjectSettings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ProjectSettings{}, err
+		}
+		return ProjectSettings{}, fmt.Errorf("read project settings: %w", err)
+	}
+
+	var settings ProjectSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return ProjectSettings{}, fmt.Errorf("parse project settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// WriteProjectSettings records settings at the project root and drops the
+// legacy copy under .methodology.
+func WriteProjectSettings(projectRoot string, settings ProjectSettings) error {
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("serialize project settings: %w", err)
+	}
+	data = append(data, '\n')
+
+	if err := os.WriteFile(filepath.Join(projectRoot, ProjectSettingsFilename), data, 0o644); err != nil {
+		return fmt.Errorf("write project settings: %w", err)
+	}
+
+	legacy := projectSettingsPath(filepath.Join(projectRoot, ".methodology"))
+	if err := os.Remove(legacy); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove %q: %w", legacy, err)
+	}
+
+	return nil
+}
+
+func projectSettingsPath(localDir string) string {
+	return filepath.Join(localDir, legacyProjectSettingsFilename)
+}
@@ -1,6 +1,7 @@
 package methodology
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -13,7 +14,14 @@ import (
 const syncStateFilename = ".spire-sync-state.json"
 
 type syncState struct {
-	Hashes map[string]string `json:"hashes"`
+	Hashes      map[string]string `json:"hashes"`
+	Projections map[string]string `json:"projections,omitempty"`
+	// Merged lists projected destinations that existed before spire merged
+	// into them, as opposed to files spire created.
+	Merged []string `json:"merged,omitempty"`
+	// MergeSources keeps the upstream document last merged into each
+	// json_merge destination, so entries removed locally are not merged back.
+	MergeSources map[string]json.RawMessage `json:"merge_sources,omitempty"`
 }
 
 func DetectDirty(localDir string) ([]string, error) {
@@ -48,17 +56,29 @@ func DetectDirty(localDir string) ([]string, error) {
 	return dedupeSorted(dirty), nil
 }
 
-func SyncAndReportChanges(localDir string, sourceDir string) ([]string, error) {
+func SyncAndReportChanges(ctx context.Context, localDir string, sourceDir string) ([]string, error) {
 	beforeHashes, err := dirFileHashes(localDir)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := copyDir(sourceDir, localDir); err != nil {
-		return nil, err
-	}
+	var afterHashes map[string]string
+	err = replaceDir(localDir, func(staging string) error {
+		if err := copyDir(ctx, localDir, staging); err != nil {
+			return err
+		}
+		if err := copyDir(ctx, sourceDir, staging); err != nil {
+			return err
+		}
 
-	afterHashes, err := dirFileHashes(localDir)
+		hashes, err := dirFileHashes(staging)
+		if err != nil {
+			return err
+		}
+		afterHashes = hashes
+
+		return writeSyncState(staging, hashes)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -77,10 +97,6 @@ func SyncAndReportChanges(localDir string, sourceDir string) ([]string, error) {
 		}
 	}
 
-	if err := writeSyncState(localDir, afterHashes); err != nil {
-		return nil, err
-	}
-
 	sort.Strings(changed)
 	return dedupeSorted(changed), nil
 }
@@ -107,7 +123,7 @@ func dirFileHashes(root string) (map[string]string, error) {
 			return err
 		}
 		rel = filepath.ToSlash(rel)
-		if rel == syncStateFilename || rel == sourceMetadataFilename {
+		if rel == syncStateFilename || rel == sourceMetadataFilename || rel == legacyProjectSettingsFilename {
 			return nil
 		}
 
@@ -158,12 +174,78 @@ func readSyncState(localDir string) (*syncState, error) {
 	if state.Hashes == nil {
 		state.Hashes = map[string]string{}
 	}
+	if state.Projections == nil {
+		state.Projections = map[string]string{}
+	}
 
 	return &state, nil
 }
 
 func writeSyncState(localDir string, hashes map[string]string) error {
 	state := syncState{Hashes: hashes}
+	existing, err := readSyncState(localDir)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		state.Projections = existing.Projections
+		state.Merged = existing.Merged
+		state.MergeSources = existing.MergeSources
+	}
+
+	return saveSyncState(localDir, state)
+}
+
+func ReadProjectionHashes(localDir string) (map[string]string, error) {
+	state, err := readSyncState(localDir)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return map[string]string{}, nil
+	}
+
+	return state.Projections, nil
+}
+
+// ReadMergedProjections returns the destinations recorded as merged into
+// files that already existed.
+func ReadMergedProjections(localDir string) ([]string, error) {
+	state, err := readSyncState(localDir)
+	if err != nil || state == nil {
+		return nil, err
+	}
+
+	return state.Merged, nil
+}
+
+// ReadMergeSources returns the upstream documents last merged into json_merge
+// destinations.
+func ReadMergeSources(localDir string) (map[string]json.RawMessage, error) {
+	state, err := readSyncState(localDir)
+	if err != nil || state == nil {
+		return nil, err
+	}
+
+	return state.MergeSources, nil
+}
+
+func WriteProjectionHashes(localDir string, projections map[string]string, merged []string, mergeSources map[string]json.RawMessage) error {
+	state, err := readSyncState(localDir)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		state = &syncState{Hashes: map[string]string{}}
+	}
+	state.Projections = projections
+	state.Merged = merged
+	state.MergeSources = mergeSources
+
+	return saveSyncState(localDir, *state)
+}
+
+func saveSyncState(localDir string, state syncState) error {
 	data, err := json.MarshalIndent(state, "", "  ")
 	if err != nil {
 		return fmt.Errorf("serialize sync state: %w", err)
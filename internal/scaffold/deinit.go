@@ -0,0 +1,227 @@
+package scaffold
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type DeinitAction string
+
+const (
+	DeinitRemove     DeinitAction = "remove"
+	DeinitStripBlock DeinitAction = "strip_block"
+	DeinitKeep       DeinitAction = "keep"
+)
+
+type DeinitStep struct {
+	Destination string
+	Action      DeinitAction
+	Reason      string
+	content     string
+}
+
+var protectedRoots = []string{"specs", "changes", "archive"}
+
+// PlanDeinit decides what to do with every root file spire projected. Files
+// are only removed when they still match what spire wrote, unless force is set;
+// files spire never wrote are always kept.
+func PlanDeinit(projectRoot string, methodologyDir string, force bool) ([]DeinitStep, error) {
+	projector, actions, err := newProjector(projectRoot, methodologyDir, ModeUpdate, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []DeinitStep
+	planned := map[string]bool{}
+
+	for _, action := range actions {
+		key := filepath.ToSlash(action.Destination)
+		planned[key] = true
+		if action.Policy == PolicyRemove {
+			continue
+		}
+
+		step, ok, err := projector.planDeinitStep(action, force)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			steps = append(steps, step)
+		}
+	}
+
+	// Destinations from targets or mappings that are no longer selected still
+	// sit in the ledger; only the recorded hash can vouch for them.
+	var orphans []string
+	for destination := range projector.ledger.hashes {
+		if !planned[destination] {
+			orphans = append(orphans, destination)
+		}
+	}
+	sort.Strings(orphans)
+
+	for _, destination := range orphans {
+		step, ok, err := projector.planDeinitStep(ProjectionAction{Destination: filepath.FromSlash(destination)}, force)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			steps = append(steps, step)
+		}
+	}
+
+	return steps, nil
+}
+
+func (p *projector) planDeinitStep(action ProjectionAction, force bool) (DeinitStep, bool, error) {
+	step := DeinitStep{Destination: filepath.ToSlash(action.Destination)}
+	if isProtectedDestination(step.Destination) {
+		step.Action = DeinitKeep
+		step.Reason = "protected directory"
+		return step, true, nil
+	}
+
+	current, err := os.ReadFile(filepath.Join(p.projectRoot, action.Destination))
+	if os.IsNotExist(err) {
+		return step, false, nil
+	}
+	if err != nil {
+		return step, false, fmt.Errorf("read destination file %q: %w", action.Destination, err)
+	}
+
+	recorded, written := p.ledger.lookup(action.Destination)
+	unmodified := written && recorded == contentHash(current)
+
+	if action.Policy == PolicyManagedBlock {
+		return p.planManagedBlockRemoval(action, step, string(current), unmodified, force)
+	}
+
+	if !unmodified && action.Source != "" {
+		desired, err := p.desiredContent(action)
+		if err != nil {
+			return step, false, err
+		}
+		if string(desired) == string(current) {
+			unmodified = true
+			written = true
+		}
+	}
+
+	switch {
+	case p.ledger.wasMerged(action.Destination):
+		step.Action = DeinitKeep
+		step.Reason = "existed before spire; merged keys left in place"
+	case unmodified:
+		step.Action = DeinitRemove
+	case !written:
+		step.Action = DeinitKeep
+		step.Reason = "not created by spire"
+	case force:
+		step.Action = DeinitRemove
+		step.Reason = "modified locally"
+	default:
+		step.Action = DeinitKeep
+		step.Reason = "modified locally; use --force to remove"
+	}
+
+	return step, true, nil
+}
+
+func (p *projector) planManagedBlockRemoval(action ProjectionAction, step DeinitStep, current string, unmodified bool, force bool) (DeinitStep, bool, error) {
+	block, _, err := findManagedBlock(current)
+	if errors.Is(err, errManagedBlockMissing) {
+		return step, false, nil
+	}
+	if err != nil {
+		step.Action = DeinitKeep
+		step.Reason = "unbalanced spire markers"
+		return step, true, nil
+	}
+
+	if !unmodified {
+		sourceData, err := os.ReadFile(action.Source)
+		if err != nil {
+			return step, false, fmt.Errorf("read source file %q: %w", action.Source, err)
+		}
+		upstream, err := managedBlockFromSource(string(sourceData))
+		if err != nil {
+			return step, false, fmt.Errorf("source %q: %v", action.Source, err)
+		}
+		unmodified = upstream == block
+	}
+
+	if !unmodified && !force {
+		step.Action = DeinitKeep
+		step.Reason = "spire block modified locally; use --force to remove"
+		return step, true, nil
+	}
+
+	stripped, err := stripManagedBlock(current)
+	if err != nil {
+		return step, false, err
+	}
+
+	if strings.TrimSpace(stripped) == "" {
+		step.Action = DeinitRemove
+	} else {
+		step.Action = DeinitStripBlock
+		step.content = stripped
+	}
+	if !unmodified {
+		step.Reason = "spire block modified locally"
+	}
+
+	return step, true, nil
+}
+
+func ApplyDeinit(ctx context.Context, projectRoot string, steps []DeinitStep) error {
+	for _, step := range steps {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("deinit: %w", err)
+		}
+
+		destination := filepath.Join(projectRoot, filepath.FromSlash(step.Destination))
+
+		switch step.Action {
+		case DeinitRemove:
+			if err := os.Remove(destination); err != nil {
+				return fmt.Errorf("remove %q: %w", step.Destination, err)
+			}
+			removeEmptyParents(projectRoot, filepath.Dir(destination))
+		case DeinitStripBlock:
+			info, err := os.Stat(destination)
+			if err != nil {
+				return fmt.Errorf("stat destination file %q: %w", destination, err)
+			}
+			if err := os.WriteFile(destination, []byte(step.content), info.Mode().Perm()); err != nil {
+				return fmt.Errorf("write destination file %q: %w", destination, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func removeEmptyParents(projectRoot string, dir string) {
+	root := filepath.Clean(projectRoot)
+	for dir = filepath.Clean(dir); dir != root && isPathWithin(root, dir); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+	}
+}
+
+func isProtectedDestination(destination string) bool {
+	first, _, _ := strings.Cut(filepath.ToSlash(filepath.Clean(destination)), "/")
+	for _, root := range protectedRoots {
+		if first == root {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+type FrontmatterTransform struct {
+	Keep []string          `json:"keep,omitempty"`
+	Set  map[string]string `json:"set,omitempty"`
+}
+
+type FrontmatterVars struct {
+	Name        string
+	ProjectName string
+}
+
+type frontmatterEntry struct {
+	key   string
+	lines []string
+}
+
+// TransformFrontmatter rewrites the frontmatter of a markdown document for a
+// different agent runtime. Keep limits the top-level keys that survive (nested
+// lines travel with their key); Set values are Go templates over vars.
+func TransformFrontmatter(content []byte, transform FrontmatterTransform, vars FrontmatterVars) ([]byte, error) {
+	frontmatter, body, _ := splitFrontmatter(string(content))
+	entries := parseFrontmatterEntries(frontmatter)
+
+	if len(transform.Keep) > 0 {
+		keep := map[string]bool{}
+		for _, key := range transform.Keep {
+			keep[key] = true
+		}
+
+		filtered := entries[:0]
+		for _, entry := range entries {
+			if keep[entry.key] {
+				filtered = append(filtered, entry)
+			}
+		}
+		entries = filtered
+	}
+
+	keys := make([]string, 0, len(transform.Set))
+	for key := range transform.Set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		tmpl, err := template.New(key).Option("missingkey=error").Parse(transform.Set[key])
+		if err != nil {
+			return nil, fmt.Errorf("parse frontmatter value for %q: %w", key, err)
+		}
+
+		var value bytes.Buffer
+		if err := tmpl.Execute(&value, vars); err != nil {
+			return nil, fmt.Errorf("render frontmatter value for %q: %w", key, err)
+		}
+
+		line := key + ": " + value.String()
+		replaced := false
+		for i := range entries {
+			if entries[i].key == key {
+				entries[i].lines = []string{line}
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			entries = append(entries, frontmatterEntry{key: key, lines: []string{line}})
+		}
+	}
+
+	if len(entries) == 0 {
+		return []byte(body), nil
+	}
+
+	var out strings.Builder
+	out.WriteString("---\n")
+	for _, entry := range entries {
+		for _, line := range entry.lines {
+			out.WriteString(line)
+			out.WriteString("\n")
+		}
+	}
+	out.WriteString("---\n")
+	out.WriteString(body)
+
+	return []byte(out.String()), nil
+}
+
+func splitFrontmatter(content string) (string, string, bool) {
+	content = strings.ReplaceAll(strings.TrimPrefix(content, "\ufeff"), "\r\n", "\n")
+	if !strings.HasPrefix(content, "---\n") {
+		return "", content, false
+	}
+
+	rest := strings.TrimPrefix(content, "---\n")
+	if strings.HasPrefix(rest, "---\n") {
+		return "", strings.TrimPrefix(rest, "---\n"), true
+	}
+
+	end := strings.Index(rest, "\n---\n")
+	if end < 0 {
+		if strings.HasSuffix(rest, "\n---") {
+			return strings.TrimSuffix(rest, "\n---") + "\n", "", true
+		}
+		return "", content, false
+	}
+
+	return rest[:end+1], rest[end+len("\n---\n"):], true
+}
+
+func parseFrontmatterFields(frontmatter string) map[string]string {
+	fields := map[string]string{}
+	for _, line := range strings.Split(frontmatter, "\n") {
+		if line == "" || line[0] == ' ' || line[0] == '\t' || line[0] == '#' {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		value = strings.Trim(value, `"'`)
+		fields[strings.TrimSpace(key)] = value
+	}
+	return fields
+}
+
+func parseFrontmatterEntries(frontmatter string) []frontmatterEntry {
+	var entries []frontmatterEntry
+	for _, line := range strings.Split(strings.TrimSuffix(frontmatter, "\n"), "\n") {
+		if line == "" && frontmatter == "" {
+			continue
+		}
+
+		if line != "" && line[0] != ' ' && line[0] != '\t' && line[0] != '#' {
+			if key, _, ok := strings.Cut(line, ":"); ok {
+				entries = append(entries, frontmatterEntry{key: strings.TrimSpace(key), lines: []string{line}})
+				continue
+			}
+		}
+
+		if len(entries) == 0 {
+			entries = append(entries, frontmatterEntry{})
+		}
+		last := &entries[len(entries)-1]
+		last.lines = append(last.lines, line)
+	}
+	return entries
+}
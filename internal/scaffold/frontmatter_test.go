@@ -0,0 +1,62 @@
+package scaffold
+
+import "testing"
+
+func TestTransformFrontmatter(t *testing.T) {
+	t.Parallel()
+
+	source := "---\ndescription: Plans features.\nmode: subagent\npermission:\n  edit: deny\n---\nBody\n"
+
+	tests := []struct {
+		name      string
+		transform FrontmatterTransform
+		want      string
+	}{
+		{
+			name:      "keep and set",
+			transform: FrontmatterTransform{Keep: []string{"description"}, Set: map[string]string{"name": "{{ .Name }}"}},
+			want:      "---\ndescription: Plans features.\nname: planner\n---\nBody\n",
+		},
+		{
+			name:      "nested lines travel with key",
+			transform: FrontmatterTransform{Keep: []string{"permission"}},
+			want:      "---\npermission:\n  edit: deny\n---\nBody\n",
+		},
+		{
+			name:      "set replaces existing key in place",
+			transform: FrontmatterTransform{Set: map[string]string{"mode": "all"}},
+			want:      "---\ndescription: Plans features.\nmode: all\npermission:\n  edit: deny\n---\nBody\n",
+		},
+		{
+			name:      "nothing kept drops frontmatter",
+			transform: FrontmatterTransform{Keep: []string{"model"}},
+			want:      "Body\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := TransformFrontmatter([]byte(source), tt.transform, FrontmatterVars{Name: "planner"})
+			if err != nil {
+				t.Fatalf("transform: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformFrontmatter_WithoutFrontmatter(t *testing.T) {
+	t.Parallel()
+
+	got, err := TransformFrontmatter([]byte("Body\n"), FrontmatterTransform{Set: map[string]string{"alwaysApply": "false"}}, FrontmatterVars{})
+	if err != nil {
+		t.Fatalf("transform: %v", err)
+	}
+	if want := "---\nalwaysApply: false\n---\nBody\n"; string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
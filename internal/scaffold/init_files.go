@@ -1,6 +1,7 @@
 package scaffold
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -43,6 +44,63 @@ func EnsureGitignoreEntry(projectRoot string, entry string) error {
 	return nil
 }
 
+func HasGitignoreEntry(projectRoot string, entry string) (bool, error) {
+	data, err := os.ReadFile(filepath.Join(projectRoot, ".gitignore"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("read .gitignore: %w", err)
+	}
+
+	entry = strings.TrimSpace(entry)
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == entry {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func RemoveGitignoreEntry(projectRoot string, entry string) (bool, error) {
+	gitignorePath := filepath.Join(projectRoot, ".gitignore")
+	entry = strings.TrimSpace(entry)
+
+	data, err := os.ReadFile(gitignorePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("read .gitignore: %w", err)
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if entry != "" && strings.TrimSpace(line) == entry {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	if len(kept) == len(lines) {
+		return false, nil
+	}
+
+	remaining := strings.Join(kept, "")
+	if strings.TrimSpace(remaining) == "" {
+		if err := os.Remove(gitignorePath); err != nil {
+			return false, fmt.Errorf("remove .gitignore: %w", err)
+		}
+		return true, nil
+	}
+
+	if err := os.WriteFile(gitignorePath, []byte(remaining), 0o644); err != nil {
+		return false, fmt.Errorf("write .gitignore: %w", err)
+	}
+
+	return true, nil
+}
+
 func copyFile(source string, destination string) error {
 	in, err := os.Open(source)
 	if err != nil {
@@ -72,53 +130,87 @@ func copyFile(source string, destination string) error {
 	return nil
 }
 
-func ApplyProjectRootInitMappings(projectRoot string, methodologyDir string, out io.Writer) error {
-	manifestPath := filepath.Join(methodologyDir, "project_root", "manifest.json")
-	manifest, err := LoadProjectRootManifest(manifestPath)
-	if err != nil {
-		return err
-	}
+func ApplyProjectRootInitMappings(ctx context.Context, projectRoot string, methodologyDir string, out io.Writer) error {
+	return applyProjectRootInit(ctx, projectRoot, methodologyDir, false, out)
+}
+
+// ApplyProjectRootMissingMappings projects only destinations that do not exist
+// yet, leaving every existing root file untouched.
+func ApplyProjectRootMissingMappings(ctx context.Context, projectRoot string, methodologyDir string, out io.Writer) error {
+	return applyProjectRootInit(ctx, projectRoot, methodologyDir, true, out)
+}
 
-	sourceRoot := methodologyDir
-	actions, err := BuildProjectRootActions(manifest, sourceRoot, ModeInit)
+// applyProjectRootInit and ApplyProjectRootUpdateMappings roll back every root
+// file they touched when an action fails or ctx is cancelled.
+func applyProjectRootInit(ctx context.Context, projectRoot string, methodologyDir string, missingOnly bool, out io.Writer) error {
+	projector, actions, err := newProjector(projectRoot, methodologyDir, ModeInit, out)
 	if err != nil {
 		return err
 	}
 
 	for _, action := range actions {
+		if err := ctx.Err(); err != nil {
+			return projector.rollback(fmt.Errorf("project root files: %w", err))
+		}
+
 		destination := filepath.Join(projectRoot, action.Destination)
 		exists, err := pathExists(destination)
 		if err != nil {
-			return err
+			return projector.rollback(err)
 		}
 
-		if exists {
-			fmt.Fprintf(out, "skipped existing: %s\n", action.Destination)
+		if missingOnly && exists {
+			if action.Policy != PolicyRemove {
+				fmt.Fprintf(out, "skipped existing: %s\n", action.Destination)
+			}
 			continue
 		}
 
-		if err := copyFile(action.Source, destination); err != nil {
-			return err
-		}
+		switch action.Policy {
+		case PolicyRemove:
+			err = projector.removeRetired(action, destination, exists)
+		case PolicyManagedBlock:
+			err = projector.managedBlock(action, destination, exists, true)
+		case PolicyAlwaysOverwrite:
+			err = projector.overwrite(action, destination, exists)
+		case PolicyOverwriteIfUnmodified, PolicyRender:
+			err = projector.overwriteIfUnmodified(action, destination, exists)
+		default:
+			if exists && action.Policy == PolicyJSONMerge {
+				err = projector.mergeJSON(action, destination)
+				break
+			}
+
+			if exists {
+				fmt.Fprintf(out, "skipped existing: %s\n", action.Destination)
+				continue
+			}
+
+			if err := projector.copy(action, destination); err != nil {
+				return projector.rollback(err)
+			}
 
-		fmt.Fprintf(out, "created: %s\n", action.Destination)
+			fmt.Fprintf(out, "created: %s\n", action.Destination)
+		}
+		if err != nil {
+			return projector.rollback(err)
+		}
 	}
 
+	if err := projector.ledger.save(); err != nil {
+		return projector.rollback(err)
+	}
 	return nil
 }
 
-func ApplyProjectRootUpdateMappings(projectRoot string, methodologyDir string, changedMethodologyFiles []string, out io.Writer) error {
-	manifestPath := filepath.Join(methodologyDir, "project_root", "manifest.json")
-	manifest, err := LoadProjectRootManifest(manifestPath)
-	if err != nil {
-		return err
-	}
-
-	sourceRoot := methodologyDir
-	actions, err := BuildProjectRootActions(manifest, sourceRoot, ModeUpdate)
+// ApplyProjectRootUpdateMappings projects the synced payload. upstream comes
+// from UpstreamHashes on the payload the sync replaced.
+func ApplyProjectRootUpdateMappings(ctx context.Context, projectRoot string, methodologyDir string, changedMethodologyFiles []string, upstream map[string]string, out io.Writer) error {
+	projector, actions, err := newProjector(projectRoot, methodologyDir, ModeUpdate, out)
 	if err != nil {
 		return err
 	}
+	projector.upstream = upstream
 
 	changedSet := map[string]bool{}
 	for _, path := range changedMethodologyFiles {
@@ -126,48 +218,72 @@ func ApplyProjectRootUpdateMappings(projectRoot string, methodologyDir string, c
 	}
 
 	for _, action := range actions {
+		if err := ctx.Err(); err != nil {
+			return projector.rollback(fmt.Errorf("project root files: %w", err))
+		}
+
 		destination := filepath.Join(projectRoot, action.Destination)
 		exists, err := pathExists(destination)
 		if err != nil {
-			return err
+			return projector.rollback(err)
 		}
 
-		sourceRel, err := filepath.Rel(methodologyDir, action.Source)
-		if err != nil {
-			return fmt.Errorf("compute source relative path for %q: %w", action.Source, err)
-		}
-		sourceRel = filepath.ToSlash(sourceRel)
+		switch action.Policy {
+		case PolicyRemove:
+			err = projector.removeRetired(action, destination, exists)
+		case PolicyManagedBlock:
+			err = projector.managedBlock(action, destination, exists, false)
+		case PolicyAlwaysOverwrite:
+			err = projector.overwrite(action, destination, exists)
+		case PolicyOverwriteIfUnmodified, PolicyRender:
+			err = projector.overwriteIfUnmodified(action, destination, exists)
+		default:
+			sourceRel, err := filepath.Rel(methodologyDir, action.Source)
+			if err != nil {
+				return projector.rollback(fmt.Errorf("compute source relative path for %q: %w", action.Source, err))
+			}
+			sourceRel = filepath.ToSlash(sourceRel)
 
-		if exists && action.Policy == PolicyNeverOverwrite {
-			if isManagedOpencodeDestination(action.Destination) && changedSet[sourceRel] {
-				if err := copyFile(action.Source, destination); err != nil {
-					return err
+			if exists && action.Policy == PolicyNeverOverwrite {
+				if action.NotifyIfSourceChanged && changedSet[sourceRel] {
+					if err := projector.noticeKept(action, destination, sourceRel); err != nil {
+						return projector.rollback(err)
+					}
 				}
-				fmt.Fprintf(out, "updated: %s\n", action.Destination)
 				continue
 			}
 
-			if action.NotifyIfSourceChanged && changedSet[sourceRel] {
-				fmt.Fprintf(out, "notice: upstream %s changed; kept existing %s\n", sourceRel, action.Destination)
+			if exists && action.Policy == PolicyIfMissing {
+				continue
 			}
-			continue
-		}
 
-		if exists && action.Policy == PolicyIfMissing {
-			continue
-		}
+			if exists && action.Policy == PolicyJSONMerge {
+				if err := projector.mergeJSON(action, destination); err != nil {
+					return projector.rollback(err)
+				}
+				continue
+			}
 
-		if err := copyFile(action.Source, destination); err != nil {
-			return err
-		}
+			if err := projector.copy(action, destination); err != nil {
+				return projector.rollback(err)
+			}
 
-		if exists {
-			fmt.Fprintf(out, "updated: %s\n", action.Destination)
-		} else {
-			fmt.Fprintf(out, "created: %s\n", action.Destination)
+			if exists {
+				fmt.Fprintf(out, "updated: %s\n", action.Destination)
+			} else {
+				fmt.Fprintf(out, "created: %s\n", action.Destination)
+			}
+		}
+		if err != nil {
+			return projector.rollback(err)
 		}
 	}
 
+	projector.report()
+
+	if err := projector.ledger.save(); err != nil {
+		return projector.rollback(err)
+	}
 	return nil
 }
 
@@ -181,8 +297,3 @@ func pathExists(path string) (bool, error) {
 	}
 	return false, fmt.Errorf("stat %q: %w", path, err)
 }
-
-func isManagedOpencodeDestination(destination string) bool {
-	destination = filepath.ToSlash(destination)
-	return strings.HasPrefix(destination, ".opencode/")
-}
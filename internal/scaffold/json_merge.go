@@ -0,0 +1,320 @@
+package scaffold
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+type jsonObject struct {
+	keys   []string
+	values map[string]any
+}
+
+// MergeJSONFile merges source into destination in place. previous is the
+// upstream document merged last time, or nil when none was recorded.
+func MergeJSONFile(source string, destination string, previous []byte) ([]string, error) {
+	upstreamData, err := os.ReadFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("read source file %q: %w", source, err)
+	}
+
+	localData, err := os.ReadFile(destination)
+	if err != nil {
+		return nil, fmt.Errorf("read destination file %q: %w", destination, err)
+	}
+
+	merged, mergedKeys, err := MergeJSON(localData, upstreamData, previous)
+	if err != nil {
+		return nil, fmt.Errorf("merge %q into %q: %w", source, destination, err)
+	}
+
+	if len(mergedKeys) == 0 {
+		return nil, nil
+	}
+
+	info, err := os.Stat(destination)
+	if err != nil {
+		return nil, fmt.Errorf("stat destination file %q: %w", destination, err)
+	}
+
+	if err := os.WriteFile(destination, merged, info.Mode().Perm()); err != nil {
+		return nil, fmt.Errorf("write destination file %q: %w", destination, err)
+	}
+
+	return mergedKeys, nil
+}
+
+// MergeJSON folds upstream into local: arrays are unioned, missing keys are
+// added, and local values win on conflict. When previous (the upstream merged
+// last time) is given, only keys and array entries upstream added since then
+// are merged, so ones removed locally stay removed. It returns the dotted key
+// paths that changed; when none did, the returned document is local unchanged.
+func MergeJSON(local []byte, upstream []byte, previous []byte) ([]byte, []string, error) {
+	localValue, err := decodeOrderedJSON(local)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse local JSON: %w", err)
+	}
+
+	upstreamValue, err := decodeOrderedJSON(upstream)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse upstream JSON: %w", err)
+	}
+
+	localObject, ok := localValue.(*jsonObject)
+	if !ok {
+		return nil, nil, errors.New("local JSON is not an object")
+	}
+	upstreamObject, ok := upstreamValue.(*jsonObject)
+	if !ok {
+		return nil, nil, errors.New("upstream JSON is not an object")
+	}
+
+	var previousObject *jsonObject
+	if previous != nil {
+		previousValue, err := decodeOrderedJSON(previous)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse previous upstream JSON: %w", err)
+		}
+		previousObject, _ = previousValue.(*jsonObject)
+	}
+
+	var mergedKeys []string
+	mergeJSONObjects(localObject, upstreamObject, previousObject, "", &mergedKeys)
+	if len(mergedKeys) == 0 {
+		return local, nil, nil
+	}
+
+	var buf bytes.Buffer
+	if err := writeOrderedJSON(&buf, localObject, ""); err != nil {
+		return nil, nil, err
+	}
+	buf.WriteString("\n")
+
+	return buf.Bytes(), mergedKeys, nil
+}
+
+// mergeJSONObjects merges upstream into local. previous may be nil; a key it
+// already had that local lacks was removed locally and is not added back.
+func mergeJSONObjects(local *jsonObject, upstream *jsonObject, previous *jsonObject, prefix string, mergedKeys *[]string) {
+	for _, key := range upstream.keys {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		upstreamValue := upstream.values[key]
+		var previousValue any
+		previouslyMerged := false
+		if previous != nil {
+			previousValue, previouslyMerged = previous.values[key]
+		}
+
+		localValue, exists := local.values[key]
+		if !exists {
+			if previouslyMerged {
+				continue
+			}
+			local.keys = append(local.keys, key)
+			local.values[key] = upstreamValue
+			*mergedKeys = append(*mergedKeys, path)
+			continue
+		}
+
+		switch typed := localValue.(type) {
+		case *jsonObject:
+			if upstreamObject, ok := upstreamValue.(*jsonObject); ok {
+				previousObject, _ := previousValue.(*jsonObject)
+				mergeJSONObjects(typed, upstreamObject, previousObject, path, mergedKeys)
+			}
+		case []any:
+			upstreamArray, ok := upstreamValue.([]any)
+			if !ok {
+				continue
+			}
+			previousArray, _ := previousValue.([]any)
+			union, changed := unionJSONArrays(typed, upstreamArray, previousArray)
+			if changed {
+				local.values[key] = union
+				*mergedKeys = append(*mergedKeys, path)
+			}
+		}
+	}
+}
+
+// unionJSONArrays appends upstream entries missing from local, skipping those
+// previous already had: local dropped them on purpose.
+func unionJSONArrays(local []any, upstream []any, previous []any) ([]any, bool) {
+	seen := map[string]bool{}
+	for _, item := range local {
+		seen[canonicalJSON(item)] = true
+	}
+	for _, item := range previous {
+		seen[canonicalJSON(item)] = true
+	}
+
+	result := local
+	changed := false
+	for _, item := range upstream {
+		key := canonicalJSON(item)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, item)
+		changed = true
+	}
+
+	return result, changed
+}
+
+func canonicalJSON(value any) string {
+	var buf bytes.Buffer
+	_ = writeOrderedJSON(&buf, value, "")
+	return buf.String()
+}
+
+func decodeOrderedJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	value, err := decodeOrderedValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected trailing data")
+	}
+
+	return value, nil
+}
+
+func decodeOrderedValue(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	switch delim {
+	case '{':
+		object := &jsonObject{values: map[string]any{}}
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, ok := keyToken.(string)
+			if !ok {
+				return nil, fmt.Errorf("unexpected object key %v", keyToken)
+			}
+
+			value, err := decodeOrderedValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, exists := object.values[key]; !exists {
+				object.keys = append(object.keys, key)
+			}
+			object.values[key] = value
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return object, nil
+	case '[':
+		array := []any{}
+		for decoder.More() {
+			value, err := decodeOrderedValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return array, nil
+	default:
+		return nil, fmt.Errorf("unexpected delimiter %q", delim)
+	}
+}
+
+func writeOrderedJSON(buf *bytes.Buffer, value any, indent string) error {
+	inner := indent + "  "
+
+	switch typed := value.(type) {
+	case *jsonObject:
+		if len(typed.keys) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		buf.WriteString("{\n")
+		for i, key := range typed.keys {
+			buf.WriteString(inner)
+			writeJSONString(buf, key)
+			buf.WriteString(": ")
+			if err := writeOrderedJSON(buf, typed.values[key], inner); err != nil {
+				return err
+			}
+			if i < len(typed.keys)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(indent)
+		buf.WriteString("}")
+	case []any:
+		if len(typed) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		buf.WriteString("[\n")
+		for i, item := range typed {
+			buf.WriteString(inner)
+			if err := writeOrderedJSON(buf, item, inner); err != nil {
+				return err
+			}
+			if i < len(typed)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(indent)
+		buf.WriteString("]")
+	case string:
+		writeJSONString(buf, typed)
+	case json.Number:
+		buf.WriteString(typed.String())
+	case bool:
+		if typed {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+	case nil:
+		buf.WriteString("null")
+	default:
+		return fmt.Errorf("unsupported JSON value %T", value)
+	}
+
+	return nil
+}
+
+func writeJSONString(buf *bytes.Buffer, value string) {
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value)
+	buf.WriteString(strings.TrimSuffix(encoded.String(), "\n"))
+}
@@ -0,0 +1,123 @@
+package scaffold
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMergeJSON_UnionsArraysAndAddsKeys(t *testing.T) {
+	t.Parallel()
+
+	local := []byte("{\n  \"theme\": \"dark\",\n  \"instructions\": [\n    \"AGENTS.md\",\n    \"docs/local.md\"\n  ]\n}\n")
+	upstream := []byte(`{"instructions": [".methodology/agents/SPIRE.md", "AGENTS.md"], "theme": "light", "share": "manual"}`)
+
+	merged, keys, err := MergeJSON(local, upstream, nil)
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	if !reflect.DeepEqual(keys, []string{"instructions", "share"}) {
+		t.Fatalf("merged keys: got %v", keys)
+	}
+
+	want := "{\n  \"theme\": \"dark\",\n  \"instructions\": [\n    \"AGENTS.md\",\n    \"docs/local.md\",\n    \".methodology/agents/SPIRE.md\"\n  ],\n  \"share\": \"manual\"\n}\n"
+	if string(merged) != want {
+		t.Fatalf("merged document:\ngot  %q\nwant %q", string(merged), want)
+	}
+}
+
+func TestMergeJSON_NestedObjectsKeepLocalValues(t *testing.T) {
+	t.Parallel()
+
+	local := []byte(`{"agent": {"build": {"model": "local"}}}`)
+	upstream := []byte(`{"agent": {"build": {"model": "upstream", "temperature": 0.2}, "plan": {"mode": "primary"}}}`)
+
+	merged, keys, err := MergeJSON(local, upstream, nil)
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	if !reflect.DeepEqual(keys, []string{"agent.build.temperature", "agent.plan"}) {
+		t.Fatalf("merged keys: got %v", keys)
+	}
+
+	want := "{\n  \"agent\": {\n    \"build\": {\n      \"model\": \"local\",\n      \"temperature\": 0.2\n    },\n    \"plan\": {\n      \"mode\": \"primary\"\n    }\n  }\n}\n"
+	if string(merged) != want {
+		t.Fatalf("merged document:\ngot  %q\nwant %q", string(merged), want)
+	}
+}
+
+func TestMergeJSON_IdempotentWhenAlreadyMerged(t *testing.T) {
+	t.Parallel()
+
+	local := []byte(`{"instructions": ["AGENTS.md"]}`)
+	upstream := []byte(`{"instructions": ["AGENTS.md", "specs/PRODUCT.md"]}`)
+
+	first, keys, err := MergeJSON(local, upstream, nil)
+	if err != nil {
+		t.Fatalf("first merge: %v", err)
+	}
+	if len(keys) != 1 {
+		t.Fatalf("first merge keys: got %v", keys)
+	}
+
+	second, keys, err := MergeJSON(first, upstream, nil)
+	if err != nil {
+		t.Fatalf("second merge: %v", err)
+	}
+	if len(keys) != 0 {
+		t.Fatalf("second merge keys: got %v, want none", keys)
+	}
+	if string(second) != string(first) {
+		t.Fatalf("second merge changed document: %q", string(second))
+	}
+}
+
+func TestMergeJSON_KeepsEntriesRemovedSincePreviousMerge(t *testing.T) {
+	t.Parallel()
+
+	previous := []byte(`{"instructions": ["SPIRE.md", "AGENTS.md"], "share": "manual"}`)
+	local := []byte(`{"instructions": ["AGENTS.md"]}`)
+	upstream := []byte(`{"instructions": ["SPIRE.md", "AGENTS.md", "specs/PRODUCT.md"], "share": "manual", "theme": "light"}`)
+
+	merged, keys, err := MergeJSON(local, upstream, previous)
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	if !reflect.DeepEqual(keys, []string{"instructions", "theme"}) {
+		t.Fatalf("merged keys: got %v", keys)
+	}
+	want := "{\n  \"instructions\": [\n    \"AGENTS.md\",\n    \"specs/PRODUCT.md\"\n  ],\n  \"theme\": \"light\"\n}\n"
+	if string(merged) != want {
+		t.Fatalf("merged: got %q, want %q", string(merged), want)
+	}
+}
+
+func TestMergeJSON_RejectsNonObject(t *testing.T) {
+	t.Parallel()
+
+	if _, _, err := MergeJSON([]byte(`["x"]`), []byte(`{}`), nil); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestMergeJSONFile_LeavesFileUntouchedWithoutChanges(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	source := filepath.Join(dir, "upstream.json")
+	destination := filepath.Join(dir, "opencode.json")
+	writeTestFile(t, source, `{"instructions": ["AGENTS.md"]}`)
+	writeTestFile(t, destination, `{"instructions":["AGENTS.md"]}`)
+
+	keys, err := MergeJSONFile(source, destination, nil)
+	if err != nil {
+		t.Fatalf("merge file: %v", err)
+	}
+	if len(keys) != 0 {
+		t.Fatalf("merged keys: got %v, want none", keys)
+	}
+	assertTestFileContent(t, destination, `{"instructions":["AGENTS.md"]}`)
+}
@@ -0,0 +1,214 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	ManagedBlockBegin = "<!-- spire:begin -->"
+	ManagedBlockEnd   = "<!-- spire:end -->"
+)
+
+var (
+	errManagedBlockMissing    = errors.New("managed block markers not found")
+	errManagedBlockUnbalanced = errors.New("managed block markers are unbalanced")
+)
+
+type blockResult string
+
+const (
+	blockUnchanged blockResult = "unchanged"
+	blockUpdated   blockResult = "updated"
+	blockInserted  blockResult = "inserted"
+)
+
+func managedBlockFromSource(source string) (string, error) {
+	block, _, err := findManagedBlock(source)
+	if errors.Is(err, errManagedBlockMissing) {
+		return wrapManagedBlock(source), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return block, nil
+}
+
+func initialManagedBlockContent(source string) string {
+	if _, _, err := findManagedBlock(source); err == nil {
+		return source
+	}
+	return wrapManagedBlock(source)
+}
+
+func wrapManagedBlock(content string) string {
+	content = strings.TrimRight(content, "\n")
+	return ManagedBlockBegin + "\n" + content + "\n" + ManagedBlockEnd
+}
+
+// findManagedBlock returns the marker-delimited block (markers included) and
+// its start offset within content.
+func findManagedBlock(content string) (string, int, error) {
+	start := strings.Index(content, ManagedBlockBegin)
+	end := strings.Index(content, ManagedBlockEnd)
+
+	if start < 0 && end < 0 {
+		return "", -1, errManagedBlockMissing
+	}
+	if start < 0 || end < 0 || end < start {
+		return "", -1, errManagedBlockUnbalanced
+	}
+
+	stop := end + len(ManagedBlockEnd)
+	if strings.Contains(content[stop:], ManagedBlockBegin) || strings.Contains(content[start+len(ManagedBlockBegin):end], ManagedBlockBegin) {
+		return "", -1, errManagedBlockUnbalanced
+	}
+
+	return content[start:stop], start, nil
+}
+
+func applyManagedBlock(existing string, block string, insert bool) (string, blockResult, error) {
+	current, start, err := findManagedBlock(existing)
+	if errors.Is(err, errManagedBlockMissing) {
+		if !insert {
+			return existing, blockUnchanged, err
+		}
+
+		var builder strings.Builder
+		builder.WriteString(existing)
+		if existing != "" {
+			if !strings.HasSuffix(existing, "\n") {
+				builder.WriteString("\n")
+			}
+			builder.WriteString("\n")
+		}
+		builder.WriteString(block)
+		builder.WriteString("\n")
+		return builder.String(), blockInserted, nil
+	}
+	if err != nil {
+		return existing, blockUnchanged, err
+	}
+
+	if current == block {
+		return existing, blockUnchanged, nil
+	}
+
+	return existing[:start] + block + existing[start+len(current):], blockUpdated, nil
+}
+
+func applyManagedBlockFile(source string, destination string, insert bool) (blockResult, error) {
+	sourceData, err := os.ReadFile(source)
+	if err != nil {
+		return blockUnchanged, fmt.Errorf("read source file %q: %w", source, err)
+	}
+
+	block, err := managedBlockFromSource(string(sourceData))
+	if err != nil {
+		return blockUnchanged, fmt.Errorf("source %q: %v", source, err)
+	}
+
+	existingData, err := os.ReadFile(destination)
+	if err != nil {
+		return blockUnchanged, fmt.Errorf("read destination file %q: %w", destination, err)
+	}
+
+	updated, result, err := applyManagedBlock(string(existingData), block, insert)
+	if err != nil {
+		return blockUnchanged, err
+	}
+	if result == blockUnchanged {
+		return result, nil
+	}
+
+	info, err := os.Stat(destination)
+	if err != nil {
+		return blockUnchanged, fmt.Errorf("stat destination file %q: %w", destination, err)
+	}
+
+	if err := os.WriteFile(destination, []byte(updated), info.Mode().Perm()); err != nil {
+		return blockUnchanged, fmt.Errorf("write destination file %q: %w", destination, err)
+	}
+
+	return result, nil
+}
+
+func projectManagedBlock(action ProjectionAction, destination string, exists bool, insert bool, out io.Writer) (bool, error) {
+	if !exists {
+		if err := writeInitialManagedBlock(action.Source, destination); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(out, "created: %s\n", action.Destination)
+		return true, nil
+	}
+
+	result, err := applyManagedBlockFile(action.Source, destination, insert)
+	switch {
+	case errors.Is(err, errManagedBlockMissing):
+		fmt.Fprintf(out, "notice: no spire block in %s; kept existing\n", action.Destination)
+		return false, nil
+	case errors.Is(err, errManagedBlockUnbalanced):
+		fmt.Fprintf(out, "notice: unbalanced spire markers in %s; kept existing\n", action.Destination)
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	switch result {
+	case blockInserted:
+		fmt.Fprintf(out, "inserted block: %s\n", action.Destination)
+	case blockUpdated:
+		fmt.Fprintf(out, "updated block: %s\n", action.Destination)
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func writeInitialManagedBlock(source string, destination string) error {
+	sourceData, err := os.ReadFile(source)
+	if err != nil {
+		return fmt.Errorf("open source file %q: %w", source, err)
+	}
+
+	content := initialManagedBlockContent(string(sourceData))
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destination), 0o755); err != nil {
+		return fmt.Errorf("create destination parent %q: %w", filepath.Dir(destination), err)
+	}
+
+	if err := os.WriteFile(destination, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("write destination file %q: %w", destination, err)
+	}
+
+	return nil
+}
+
+// stripManagedBlock removes the managed block and the blank line that
+// applyManagedBlock puts in front of it.
+func stripManagedBlock(existing string) (string, error) {
+	block, start, err := findManagedBlock(existing)
+	if err != nil {
+		return existing, err
+	}
+
+	before := strings.TrimRight(existing[:start], "\n")
+	after := strings.TrimLeft(existing[start+len(block):], "\n")
+
+	switch {
+	case before == "":
+		return after, nil
+	case after == "":
+		return before + "\n", nil
+	default:
+		return before + "\n\n" + after, nil
+	}
+}
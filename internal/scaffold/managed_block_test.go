@@ -0,0 +1,116 @@
+package scaffold
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestApplyManagedBlock_ReplacesOnlyManagedRegion(t *testing.T) {
+	t.Parallel()
+
+	existing := "# Local\nintro\n\n<!-- spire:begin -->\nold rules\n<!-- spire:end -->\n\n## Mine\nkeep\n"
+	block := "<!-- spire:begin -->\nnew rules\n<!-- spire:end -->"
+
+	got, result, err := applyManagedBlock(existing, block, false)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if result != blockUpdated {
+		t.Fatalf("result: got %q, want %q", result, blockUpdated)
+	}
+
+	want := "# Local\nintro\n\n<!-- spire:begin -->\nnew rules\n<!-- spire:end -->\n\n## Mine\nkeep\n"
+	if got != want {
+		t.Fatalf("content:\ngot  %q\nwant %q", got, want)
+	}
+
+	again, result, err := applyManagedBlock(got, block, false)
+	if err != nil {
+		t.Fatalf("second apply: %v", err)
+	}
+	if result != blockUnchanged || again != got {
+		t.Fatalf("second apply was not a no-op: result=%q content=%q", result, again)
+	}
+}
+
+func TestApplyManagedBlock_InsertsWhenMissing(t *testing.T) {
+	t.Parallel()
+
+	block := wrapManagedBlock("rules\n")
+
+	got, result, err := applyManagedBlock("# Local\nkeep me", block, true)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if result != blockInserted {
+		t.Fatalf("result: got %q, want %q", result, blockInserted)
+	}
+
+	want := "# Local\nkeep me\n\n<!-- spire:begin -->\nrules\n<!-- spire:end -->\n"
+	if got != want {
+		t.Fatalf("content:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func TestApplyManagedBlock_MissingWithoutInsertLeavesContent(t *testing.T) {
+	t.Parallel()
+
+	got, _, err := applyManagedBlock("# Local\n", wrapManagedBlock("rules"), false)
+	if !errors.Is(err, errManagedBlockMissing) {
+		t.Fatalf("error: got %v, want errManagedBlockMissing", err)
+	}
+	if got != "# Local\n" {
+		t.Fatalf("content changed: %q", got)
+	}
+}
+
+func TestApplyManagedBlock_UnbalancedMarkersRejected(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := applyManagedBlock("<!-- spire:begin -->\nno end\n", wrapManagedBlock("rules"), true)
+	if !errors.Is(err, errManagedBlockUnbalanced) {
+		t.Fatalf("error: got %v, want errManagedBlockUnbalanced", err)
+	}
+}
+
+func TestManagedBlockFromSource_UsesMarkedRegion(t *testing.T) {
+	t.Parallel()
+
+	source := "# Project\n<!-- spire:begin -->\nrules\n<!-- spire:end -->\n## Local\n"
+	block, err := managedBlockFromSource(source)
+	if err != nil {
+		t.Fatalf("block: %v", err)
+	}
+	if block != "<!-- spire:begin -->\nrules\n<!-- spire:end -->" {
+		t.Fatalf("block: got %q", block)
+	}
+	if initialManagedBlockContent(source) != source {
+		t.Fatalf("initial content should keep source verbatim")
+	}
+}
+
+func TestStripManagedBlock(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		existing string
+		want     string
+	}{
+		{name: "appended block", existing: "# Notes\n\n" + ManagedBlockBegin + "\nrules\n" + ManagedBlockEnd + "\n", want: "# Notes\n"},
+		{name: "block in the middle", existing: "# Notes\n\n" + ManagedBlockBegin + "\nrules\n" + ManagedBlockEnd + "\n\n## Local\n", want: "# Notes\n\n## Local\n"},
+		{name: "only block", existing: ManagedBlockBegin + "\nrules\n" + ManagedBlockEnd + "\n", want: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := stripManagedBlock(tc.existing)
+			if err != nil {
+				t.Fatalf("strip: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -5,28 +5,54 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
 type CopyPolicy string
 
 const (
-	PolicyIfMissing      CopyPolicy = "if_missing"
-	PolicyNeverOverwrite CopyPolicy = "never_overwrite"
+	PolicyIfMissing             CopyPolicy = "if_missing"
+	PolicyNeverOverwrite        CopyPolicy = "never_overwrite"
+	PolicyJSONMerge             CopyPolicy = "json_merge"
+	PolicyManagedBlock          CopyPolicy = "managed_block"
+	PolicyAlwaysOverwrite       CopyPolicy = "always_overwrite"
+	PolicyOverwriteIfUnmodified CopyPolicy = "overwrite_if_unmodified"
+	PolicyRemove                CopyPolicy = "remove"
+	PolicyRender                CopyPolicy = "render"
 )
 
+const CurrentManifestVersion = 2
+
 type ProjectRootManifest struct {
-	Version  int               `json:"version"`
+	Version  int                      `json:"version"`
+	Mappings []ProjectRootRule        `json:"mappings"`
+	Targets  map[string]ProjectTarget `json:"targets,omitempty"`
+}
+
+type ProjectTarget struct {
 	Mappings []ProjectRootRule `json:"mappings"`
 }
 
 type ProjectRootRule struct {
-	Source                string     `json:"source"`
-	Destination           string     `json:"destination"`
-	OnInit                CopyPolicy `json:"on_init"`
-	OnUpdate              CopyPolicy `json:"on_update"`
-	NotifyIfSourceChanged bool       `json:"notify_if_source_changed"`
+	Source                string                `json:"source"`
+	Destination           string                `json:"destination"`
+	OnInit                CopyPolicy            `json:"on_init"`
+	OnUpdate              CopyPolicy            `json:"on_update"`
+	NotifyIfSourceChanged bool                  `json:"notify_if_source_changed"`
+	Include               []string              `json:"include,omitempty"`
+	Exclude               []string              `json:"exclude,omitempty"`
+	When                  *RuleCondition        `json:"when,omitempty"`
+	Extension             string                `json:"extension,omitempty"`
+	Frontmatter           *FrontmatterTransform `json:"frontmatter,omitempty"`
+}
+
+type RuleCondition struct {
+	Profile      string `json:"profile,omitempty"`
+	FileExists   string `json:"file_exists,omitempty"`
+	AgentRuntime string `json:"agent_runtime,omitempty"`
 }
 
 type ManifestError struct {
@@ -60,6 +86,8 @@ func LoadProjectRootManifest(filePath string) (ProjectRootManifest, error) {
 		return ProjectRootManifest{}, &ManifestError{Kind: "schema", Err: err}
 	}
 
+	manifest = MigrateProjectRootManifest(manifest)
+
 	if err := ValidateProjectRootManifest(manifest); err != nil {
 		return ProjectRootManifest{}, err
 	}
@@ -67,8 +95,29 @@ func LoadProjectRootManifest(filePath string) (ProjectRootManifest, error) {
 	return manifest, nil
 }
 
-func ValidateProjectRootManifest(manifest ProjectRootManifest) error {
+// MigrateProjectRootManifest upgrades older manifest versions in place. Version 1
+// implicitly refreshed never_overwrite rules under .opencode/; version 2 spells
+// that out as overwrite_if_unmodified so local edits still survive.
+func MigrateProjectRootManifest(manifest ProjectRootManifest) ProjectRootManifest {
 	if manifest.Version != 1 {
+		return manifest
+	}
+
+	mappings := make([]ProjectRootRule, len(manifest.Mappings))
+	for i, mapping := range manifest.Mappings {
+		if mapping.OnUpdate == PolicyNeverOverwrite && strings.HasPrefix(filepath.ToSlash(filepath.Clean(mapping.Destination)), ".opencode/") {
+			mapping.OnUpdate = PolicyOverwriteIfUnmodified
+		}
+		mappings[i] = mapping
+	}
+
+	manifest.Version = CurrentManifestVersion
+	manifest.Mappings = mappings
+	return manifest
+}
+
+func ValidateProjectRootManifest(manifest ProjectRootManifest) error {
+	if manifest.Version != CurrentManifestVersion {
 		return &ManifestError{Kind: "validation", Field: "version", Err: fmt.Errorf("unsupported version %d", manifest.Version)}
 	}
 
@@ -77,37 +126,102 @@ func ValidateProjectRootManifest(manifest ProjectRootManifest) error {
 	}
 
 	for i, mapping := range manifest.Mappings {
-		fieldPrefix := fmt.Sprintf("mappings[%d]", i)
+		if err := validateProjectRootRule(mapping, fmt.Sprintf("mappings[%d]", i)); err != nil {
+			return err
+		}
+	}
 
+	for _, name := range sortedTargetNames(manifest.Targets) {
+		if strings.TrimSpace(name) == "" || strings.ContainsAny(name, ", ") {
+			return &ManifestError{Kind: "validation", Field: "targets", Err: fmt.Errorf("invalid target name %q", name)}
+		}
+
+		for i, mapping := range manifest.Targets[name].Mappings {
+			if err := validateProjectRootRule(mapping, fmt.Sprintf("targets.%s.mappings[%d]", name, i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateProjectRootRule(mapping ProjectRootRule, fieldPrefix string) error {
+	retired := mapping.OnInit == PolicyRemove && mapping.OnUpdate == PolicyRemove
+	if !retired || mapping.Source != "" {
 		if err := validateManifestPath(mapping.Source); err != nil {
 			return &ManifestError{Kind: "validation", Field: fieldPrefix + ".source", Err: err}
 		}
+	}
+
+	if err := validateManifestPath(mapping.Destination); err != nil {
+		return &ManifestError{Kind: "validation", Field: fieldPrefix + ".destination", Err: err}
+	}
+
+	if err := validatePolicy(mapping.OnInit); err != nil {
+		return &ManifestError{Kind: "validation", Field: fieldPrefix + ".on_init", Err: err}
+	}
+
+	if err := validatePolicy(mapping.OnUpdate); err != nil {
+		return &ManifestError{Kind: "validation", Field: fieldPrefix + ".on_update", Err: err}
+	}
 
-		if err := validateManifestPath(mapping.Destination); err != nil {
-			return &ManifestError{Kind: "validation", Field: fieldPrefix + ".destination", Err: err}
+	if mapping.When != nil && mapping.When.FileExists != "" {
+		if err := validateManifestPath(mapping.When.FileExists); err != nil {
+			return &ManifestError{Kind: "validation", Field: fieldPrefix + ".when.file_exists", Err: err}
 		}
+	}
 
-		if err := validatePolicy(mapping.OnInit); err != nil {
-			return &ManifestError{Kind: "validation", Field: fieldPrefix + ".on_init", Err: err}
+	for j, pattern := range mapping.Include {
+		if err := validateFilterPattern(pattern); err != nil {
+			return &ManifestError{Kind: "validation", Field: fmt.Sprintf("%s.include[%d]", fieldPrefix, j), Err: err}
 		}
+	}
 
-		if err := validatePolicy(mapping.OnUpdate); err != nil {
-			return &ManifestError{Kind: "validation", Field: fieldPrefix + ".on_update", Err: err}
+	for j, pattern := range mapping.Exclude {
+		if err := validateFilterPattern(pattern); err != nil {
+			return &ManifestError{Kind: "validation", Field: fmt.Sprintf("%s.exclude[%d]", fieldPrefix, j), Err: err}
 		}
 	}
 
+	if mapping.Extension != "" && (!strings.HasPrefix(mapping.Extension, ".") || strings.ContainsAny(mapping.Extension, "/\\")) {
+		return &ManifestError{Kind: "validation", Field: fieldPrefix + ".extension", Err: fmt.Errorf("invalid extension %q", mapping.Extension)}
+	}
+
 	return nil
 }
 
+func sortedTargetNames(targets map[string]ProjectTarget) []string {
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func validatePolicy(policy CopyPolicy) error {
 	switch policy {
-	case PolicyIfMissing, PolicyNeverOverwrite:
+	case PolicyIfMissing, PolicyNeverOverwrite, PolicyJSONMerge, PolicyManagedBlock,
+		PolicyAlwaysOverwrite, PolicyOverwriteIfUnmodified, PolicyRemove, PolicyRender:
 		return nil
 	default:
 		return fmt.Errorf("unknown policy %q", policy)
 	}
 }
 
+func validateFilterPattern(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return errors.New("pattern cannot be empty")
+	}
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	return nil
+}
+
 func validateManifestPath(value string) error {
 	if strings.TrimSpace(value) == "" {
 		return errors.New("path cannot be empty")
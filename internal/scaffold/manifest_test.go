@@ -14,7 +14,7 @@ func TestLoadProjectRootManifest_Valid(t *testing.T) {
 	path := filepath.Join(dir, "manifest.json")
 
 	manifestJSON := `{
-	  "version": 1,
+	  "version": 2,
 	  "mappings": [
 	    {
 	      "source": "local_agents.md",
@@ -35,8 +35,8 @@ func TestLoadProjectRootManifest_Valid(t *testing.T) {
 		t.Fatalf("load manifest: %v", err)
 	}
 
-	if manifest.Version != 1 {
-		t.Fatalf("version: got %d, want 1", manifest.Version)
+	if manifest.Version != 2 {
+		t.Fatalf("version: got %d, want 2", manifest.Version)
 	}
 
 	if len(manifest.Mappings) != 1 {
@@ -168,3 +168,73 @@ func TestLoadProjectRootManifest_UnknownPolicyRejected(t *testing.T) {
 		t.Fatalf("kind: got %q, want validation", typedErr.Kind)
 	}
 }
+
+func TestLoadProjectRootManifest_MigratesVersion1(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "manifest.json")
+
+	manifestJSON := `{
+	  "version": 1,
+	  "mappings": [
+	    {
+	      "source": "local_agents.md",
+	      "destination": "AGENTS.md",
+	      "on_init": "if_missing",
+	      "on_update": "never_overwrite"
+	    },
+	    {
+	      "source": "subagents/verifier.md",
+	      "destination": ".opencode/agents/verifier.md",
+	      "on_init": "if_missing",
+	      "on_update": "never_overwrite"
+	    }
+	  ]
+	}`
+
+	if err := os.WriteFile(path, []byte(manifestJSON), 0o600); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	manifest, err := LoadProjectRootManifest(path)
+	if err != nil {
+		t.Fatalf("load manifest: %v", err)
+	}
+
+	if manifest.Version != CurrentManifestVersion {
+		t.Fatalf("version: got %d, want %d", manifest.Version, CurrentManifestVersion)
+	}
+	if manifest.Mappings[0].OnUpdate != PolicyNeverOverwrite {
+		t.Fatalf("AGENTS.md on_update: got %q", manifest.Mappings[0].OnUpdate)
+	}
+	if manifest.Mappings[1].OnUpdate != PolicyOverwriteIfUnmodified {
+		t.Fatalf(".opencode on_update: got %q", manifest.Mappings[1].OnUpdate)
+	}
+}
+
+func TestLoadProjectRootManifest_RemoveRuleWithoutSource(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "manifest.json")
+
+	manifestJSON := `{
+	  "version": 2,
+	  "mappings": [
+	    {
+	      "destination": ".opencode/agents/retired.md",
+	      "on_init": "remove",
+	      "on_update": "remove"
+	    }
+	  ]
+	}`
+
+	if err := os.WriteFile(path, []byte(manifestJSON), 0o600); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	if _, err := LoadProjectRootManifest(path); err != nil {
+		t.Fatalf("load manifest: %v", err)
+	}
+}
@@ -0,0 +1,260 @@
+package scaffold
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	IssueMissingInstruction = "missing_instruction"
+	IssueMissingReference   = "missing_reference"
+	IssueUnknownAgent       = "unknown_agent"
+	IssueDuplicate          = "duplicate"
+	IssueInvalidAgent       = "invalid_agent"
+)
+
+var methodologyReferencePattern = regexp.MustCompile(`\.methodology/[A-Za-z0-9_./-]+`)
+
+type ValidationIssue struct {
+	Kind    string
+	Path    string
+	Message string
+}
+
+func (i ValidationIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Path, i.Message)
+}
+
+type opencodeConfig struct {
+	Instructions []string                   `json:"instructions"`
+	Agent        map[string]json.RawMessage `json:"agent"`
+	DefaultAgent string                     `json:"default_agent"`
+}
+
+func ValidateOpencodeConfig(projectRoot string) ([]ValidationIssue, error) {
+	var issues []ValidationIssue
+
+	config, found, err := readOpencodeConfig(filepath.Join(projectRoot, "opencode.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	if found {
+		seen := map[string]bool{}
+		for _, instruction := range config.Instructions {
+			normalized := filepath.ToSlash(filepath.Clean(strings.TrimSpace(instruction)))
+			if seen[normalized] {
+				issues = append(issues, ValidationIssue{
+					Kind:    IssueDuplicate,
+					Path:    "opencode.json",
+					Message: fmt.Sprintf("instruction %q is listed more than once", instruction),
+				})
+				continue
+			}
+			seen[normalized] = true
+
+			exists, err := instructionExists(projectRoot, instruction)
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				issues = append(issues, ValidationIssue{
+					Kind:    IssueMissingInstruction,
+					Path:    "opencode.json",
+					Message: fmt.Sprintf("instruction %q not found", instruction),
+				})
+			}
+		}
+	}
+
+	fileAgents, agentIssues, err := validateAgentFiles(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	issues = append(issues, agentIssues...)
+
+	if found {
+		names := make([]string, 0, len(config.Agent))
+		for name := range config.Agent {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if path, ok := fileAgents[name]; ok {
+				issues = append(issues, ValidationIssue{
+					Kind:    IssueDuplicate,
+					Path:    "opencode.json",
+					Message: fmt.Sprintf("agent %q is also defined in %s", name, path),
+				})
+			}
+		}
+
+		defaultAgent := strings.TrimSpace(config.DefaultAgent)
+		if defaultAgent != "" {
+			_, inConfig := config.Agent[defaultAgent]
+			_, inFiles := fileAgents[defaultAgent]
+			if !inConfig && !inFiles && !isBuiltinOpencodeAgent(defaultAgent) {
+				issues = append(issues, ValidationIssue{
+					Kind:    IssueUnknownAgent,
+					Path:    "opencode.json",
+					Message: fmt.Sprintf("default_agent %q is not defined", defaultAgent),
+				})
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+func readOpencodeConfig(path string) (opencodeConfig, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return opencodeConfig{}, false, nil
+		}
+		return opencodeConfig{}, false, fmt.Errorf("read opencode.json: %w", err)
+	}
+
+	var config opencodeConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return opencodeConfig{}, false, fmt.Errorf("parse opencode.json: %w", err)
+	}
+
+	return config, true, nil
+}
+
+func instructionExists(projectRoot string, instruction string) (bool, error) {
+	instruction = strings.TrimSpace(instruction)
+	if instruction == "" {
+		return false, nil
+	}
+
+	if strings.ContainsAny(instruction, "*?[") {
+		matches, err := filepath.Glob(filepath.Join(projectRoot, filepath.FromSlash(instruction)))
+		if err != nil {
+			return false, fmt.Errorf("match instruction pattern %q: %w", instruction, err)
+		}
+		return len(matches) > 0, nil
+	}
+
+	path := instruction
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectRoot, filepath.FromSlash(instruction))
+	}
+	return pathExists(path)
+}
+
+func validateAgentFiles(projectRoot string) (map[string]string, []ValidationIssue, error) {
+	agentsDir := filepath.Join(projectRoot, ".opencode", "agents")
+	entries, err := os.ReadDir(agentsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil, nil
+		}
+		return nil, nil, fmt.Errorf("read %s: %w", agentsDir, err)
+	}
+
+	agents := map[string]string{}
+	var issues []ValidationIssue
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		ext := filepath.Ext(entry.Name())
+		if ext != ".md" && ext != ".json" {
+			continue
+		}
+
+		name := strings.TrimSuffix(entry.Name(), ext)
+		rel := filepath.ToSlash(filepath.Join(".opencode", "agents", entry.Name()))
+		if previous, ok := agents[name]; ok {
+			issues = append(issues, ValidationIssue{
+				Kind:    IssueDuplicate,
+				Path:    rel,
+				Message: fmt.Sprintf("agent %q is also defined in %s", name, previous),
+			})
+		} else {
+			agents[name] = rel
+		}
+
+		if ext != ".md" {
+			continue
+		}
+
+		fileIssues, err := validateAgentMarkdown(projectRoot, rel)
+		if err != nil {
+			return nil, nil, err
+		}
+		issues = append(issues, fileIssues...)
+	}
+
+	return agents, issues, nil
+}
+
+func validateAgentMarkdown(projectRoot string, rel string) ([]ValidationIssue, error) {
+	data, err := os.ReadFile(filepath.Join(projectRoot, filepath.FromSlash(rel)))
+	if err != nil {
+		return nil, fmt.Errorf("read agent file %q: %w", rel, err)
+	}
+
+	frontmatter, body, ok := splitFrontmatter(string(data))
+	if !ok {
+		return []ValidationIssue{{
+			Kind:    IssueInvalidAgent,
+			Path:    rel,
+			Message: "missing frontmatter",
+		}}, nil
+	}
+
+	var issues []ValidationIssue
+	fields := parseFrontmatterFields(frontmatter)
+	switch mode := fields["mode"]; mode {
+	case "", "primary", "subagent", "all":
+	default:
+		issues = append(issues, ValidationIssue{
+			Kind:    IssueInvalidAgent,
+			Path:    rel,
+			Message: fmt.Sprintf("unknown mode %q", mode),
+		})
+	}
+
+	seen := map[string]bool{}
+	for _, reference := range methodologyReferencePattern.FindAllString(body, -1) {
+		reference = strings.TrimRight(reference, ".")
+		if seen[reference] {
+			continue
+		}
+		seen[reference] = true
+
+		exists, err := pathExists(filepath.Join(projectRoot, filepath.FromSlash(reference)))
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			issues = append(issues, ValidationIssue{
+				Kind:    IssueMissingReference,
+				Path:    rel,
+				Message: fmt.Sprintf("referenced file %q not found", reference),
+			})
+		}
+	}
+
+	return issues, nil
+}
+
+func isBuiltinOpencodeAgent(name string) bool {
+	switch name {
+	case "build", "plan", "general", "explore":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,112 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateOpencodeConfig_ValidProject(t *testing.T) {
+	t.Parallel()
+
+	projectRoot := t.TempDir()
+	writeTestFile(t, filepath.Join(projectRoot, ".methodology", "agents", "SPIRE.md"), "# SPIRE\n")
+	writeTestFile(t, filepath.Join(projectRoot, "AGENTS.md"), "# Project\n")
+	writeTestFile(t, filepath.Join(projectRoot, "opencode.json"), `{"instructions": [".methodology/agents/SPIRE.md", "AGENTS.md"]}`)
+	writeTestFile(t, filepath.Join(projectRoot, ".opencode", "agents", "planner.md"), "---\nmode: subagent\n---\nRead `.methodology/agents/SPIRE.md`.\n")
+
+	issues, err := ValidateOpencodeConfig(projectRoot)
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Fatalf("issues: got %v, want none", issues)
+	}
+}
+
+func TestValidateOpencodeConfig_ReportsMissingInstructions(t *testing.T) {
+	t.Parallel()
+
+	projectRoot := t.TempDir()
+	writeTestFile(t, filepath.Join(projectRoot, "opencode.json"), `{"instructions": [".methodology/templates/AGENTS.md", "docs/*.md"]}`)
+
+	issues, err := ValidateOpencodeConfig(projectRoot)
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	assertIssue(t, issues, IssueMissingInstruction, ".methodology/templates/AGENTS.md")
+	assertIssue(t, issues, IssueMissingInstruction, "docs/*.md")
+}
+
+func TestValidateOpencodeConfig_ReportsAgentProblems(t *testing.T) {
+	t.Parallel()
+
+	projectRoot := t.TempDir()
+	writeTestFile(t, filepath.Join(projectRoot, "opencode.json"), `{
+  "instructions": ["AGENTS.md", "./AGENTS.md"],
+  "agent": {"planner": {"mode": "subagent"}},
+  "default_agent": "reviewer"
+}`)
+	writeTestFile(t, filepath.Join(projectRoot, "AGENTS.md"), "# Project\n")
+	writeTestFile(t, filepath.Join(projectRoot, ".opencode", "agents", "planner.md"), "---\nmode: subagent\n---\nRead .methodology/agents/MISSING.md.\n")
+	writeTestFile(t, filepath.Join(projectRoot, ".opencode", "agents", "writer.md"), "---\nmode: helper\n---\nbody\n")
+	writeTestFile(t, filepath.Join(projectRoot, ".opencode", "agents", "writer.json"), "{}")
+	writeTestFile(t, filepath.Join(projectRoot, ".opencode", "agents", "bare.md"), "no frontmatter\n")
+
+	issues, err := ValidateOpencodeConfig(projectRoot)
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	assertIssue(t, issues, IssueDuplicate, `instruction "./AGENTS.md"`)
+	assertIssue(t, issues, IssueDuplicate, `agent "planner"`)
+	assertIssue(t, issues, IssueDuplicate, `agent "writer"`)
+	assertIssue(t, issues, IssueUnknownAgent, `"reviewer"`)
+	assertIssue(t, issues, IssueMissingReference, ".methodology/agents/MISSING.md")
+	assertIssue(t, issues, IssueInvalidAgent, `unknown mode "helper"`)
+	assertIssue(t, issues, IssueInvalidAgent, "missing frontmatter")
+}
+
+func TestValidateOpencodeConfig_InvalidJSON(t *testing.T) {
+	t.Parallel()
+
+	projectRoot := t.TempDir()
+	writeTestFile(t, filepath.Join(projectRoot, "opencode.json"), `{"instructions": [`)
+
+	if _, err := ValidateOpencodeConfig(projectRoot); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func assertIssue(t *testing.T, issues []ValidationIssue, kind string, fragment string) {
+	t.Helper()
+	for _, issue := range issues {
+		if issue.Kind == kind && strings.Contains(issue.Message, fragment) {
+			return
+		}
+	}
+	t.Fatalf("missing %s issue containing %q; got %v", kind, fragment, issues)
+}
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir parent for %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write file %s: %v", path, err)
+	}
+}
+
+func assertTestFileContent(t *testing.T, path string, want string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	if string(data) != want {
+		t.Fatalf("file %s: got %q, want %q", path, string(data), want)
+	}
+}
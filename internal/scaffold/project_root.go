@@ -2,7 +2,10 @@ package scaffold
 
 import (
 	"fmt"
+	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -18,39 +21,338 @@ type ProjectionAction struct {
 	Destination           string
 	Policy                CopyPolicy
 	NotifyIfSourceChanged bool
+	Frontmatter           *FrontmatterTransform
 }
 
-func BuildProjectRootActions(manifest ProjectRootManifest, sourceRoot string, mode ProjectionMode) ([]ProjectionAction, error) {
+const DefaultAgentRuntime = "opencode"
+
+type ProjectionContext struct {
+	ProjectRoot   string
+	Profile       string
+	AgentRuntimes []string
+}
+
+type expandedMapping struct {
+	source      string
+	destination string
+	explicit    bool
+}
+
+func BuildProjectRootActions(manifest ProjectRootManifest, sourceRoot string, mode ProjectionMode, ctx ProjectionContext) ([]ProjectionAction, error) {
 	if err := ValidateProjectRootManifest(manifest); err != nil {
 		return nil, err
 	}
 
+	rules, err := selectTargetRules(manifest, ctx.AgentRuntimes)
+	if err != nil {
+		return nil, err
+	}
+
 	sourceRoot = filepath.Clean(sourceRoot)
-	actions := make([]ProjectionAction, 0, len(manifest.Mappings))
+	actions := make([]ProjectionAction, 0, len(rules))
+	positions := map[string]int{}
+	explicit := map[string]bool{}
+
+	for _, mapping := range rules {
+		applies, err := ruleApplies(mapping.When, ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !applies {
+			continue
+		}
 
-	for _, mapping := range manifest.Mappings {
 		policy, err := policyForMode(mapping, mode)
 		if err != nil {
 			return nil, err
 		}
 
-		source := filepath.Join(sourceRoot, mapping.Source)
-		cleanSource := filepath.Clean(source)
-		if !isPathWithin(sourceRoot, cleanSource) {
-			return nil, fmt.Errorf("source escapes root: %s", mapping.Source)
+		expanded, err := expandProjectRootRule(mapping, sourceRoot)
+		if err != nil {
+			return nil, err
 		}
 
-		actions = append(actions, ProjectionAction{
-			Source:                cleanSource,
-			Destination:           filepath.Clean(mapping.Destination),
-			Policy:                policy,
-			NotifyIfSourceChanged: mapping.NotifyIfSourceChanged,
-		})
+		for _, entry := range expanded {
+			action := ProjectionAction{
+				Source:                entry.source,
+				Destination:           entry.destination,
+				Policy:                policy,
+				NotifyIfSourceChanged: mapping.NotifyIfSourceChanged,
+				Frontmatter:           mapping.Frontmatter,
+			}
+
+			key := filepath.ToSlash(entry.destination)
+			if index, ok := positions[key]; ok {
+				// A rule naming a single file overrides whatever a directory or
+				// glob rule expanded to the same destination.
+				if entry.explicit && !explicit[key] {
+					actions[index] = action
+					explicit[key] = true
+				}
+				continue
+			}
+
+			positions[key] = len(actions)
+			explicit[key] = entry.explicit
+			actions = append(actions, action)
+		}
 	}
 
 	return actions, nil
 }
 
+// selectTargetRules returns the shared mappings followed by the mappings of each
+// selected target. With no explicit selection the default runtime is projected.
+func selectTargetRules(manifest ProjectRootManifest, targets []string) ([]ProjectRootRule, error) {
+	if len(targets) == 0 {
+		targets = []string{DefaultAgentRuntime}
+	}
+
+	if err := CheckTargets(manifest, targets); err != nil {
+		return nil, err
+	}
+
+	rules := append([]ProjectRootRule{}, manifest.Mappings...)
+	seen := map[string]bool{}
+	for _, name := range targets {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		rules = append(rules, manifest.Targets[name].Mappings...)
+	}
+
+	return rules, nil
+}
+
+// CheckTargets reports a target the manifest does not define. The default
+// runtime is always accepted because manifests predating targets keep its
+// mappings at the top level.
+func CheckTargets(manifest ProjectRootManifest, targets []string) error {
+	for _, name := range targets {
+		if _, ok := manifest.Targets[name]; ok || name == DefaultAgentRuntime {
+			continue
+		}
+
+		available := sortedTargetNames(manifest.Targets)
+		if _, ok := manifest.Targets[DefaultAgentRuntime]; !ok {
+			available = append([]string{DefaultAgentRuntime}, available...)
+		}
+		return fmt.Errorf("unknown target %q (available: %s)", name, strings.Join(available, ", "))
+	}
+
+	return nil
+}
+
+func expandProjectRootRule(mapping ProjectRootRule, sourceRoot string) ([]expandedMapping, error) {
+	destination := filepath.Clean(mapping.Destination)
+	if mapping.Source == "" {
+		return []expandedMapping{{destination: destination, explicit: true}}, nil
+	}
+
+	if hasGlobMeta(mapping.Source) {
+		return expandGlobRule(mapping, sourceRoot)
+	}
+
+	source := filepath.Clean(filepath.Join(sourceRoot, mapping.Source))
+	if !isPathWithin(sourceRoot, source) {
+		return nil, fmt.Errorf("source escapes root: %s", mapping.Source)
+	}
+
+	info, err := os.Stat(source)
+	if err == nil && info.IsDir() {
+		files, err := listFiles(source)
+		if err != nil {
+			return nil, err
+		}
+		return filterExpandedFiles(mapping, sourceRoot, source, files)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("stat source %q: %w", source, err)
+	}
+
+	return []expandedMapping{{source: source, destination: destination, explicit: true}}, nil
+}
+
+func expandGlobRule(mapping ProjectRootRule, sourceRoot string) ([]expandedMapping, error) {
+	pattern := filepath.Join(sourceRoot, filepath.FromSlash(mapping.Source))
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("match source pattern %q: %w", mapping.Source, err)
+	}
+
+	base := filepath.Join(sourceRoot, filepath.FromSlash(globStaticPrefix(mapping.Source)))
+
+	var files []string
+	for _, match := range matches {
+		match = filepath.Clean(match)
+		if !isPathWithin(sourceRoot, match) {
+			return nil, fmt.Errorf("source escapes root: %s", mapping.Source)
+		}
+
+		info, err := os.Stat(match)
+		if err != nil {
+			return nil, fmt.Errorf("stat source %q: %w", match, err)
+		}
+
+		if !info.IsDir() {
+			files = append(files, match)
+			continue
+		}
+
+		nested, err := listFiles(match)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, nested...)
+	}
+
+	return filterExpandedFiles(mapping, sourceRoot, base, files)
+}
+
+func filterExpandedFiles(mapping ProjectRootRule, sourceRoot string, base string, files []string) ([]expandedMapping, error) {
+	sort.Strings(files)
+
+	expanded := make([]expandedMapping, 0, len(files))
+	for _, file := range files {
+		if !isPathWithin(sourceRoot, file) {
+			return nil, fmt.Errorf("source escapes root: %s", mapping.Source)
+		}
+
+		rel, err := filepath.Rel(base, file)
+		if err != nil {
+			return nil, fmt.Errorf("compute relative path for %q: %w", file, err)
+		}
+		rel = filepath.ToSlash(rel)
+
+		if !matchesRuleFilters(rel, mapping.Include, mapping.Exclude) {
+			continue
+		}
+
+		destination := filepath.Clean(filepath.Join(mapping.Destination, filepath.FromSlash(rel)))
+		if mapping.Extension != "" {
+			destination = strings.TrimSuffix(destination, filepath.Ext(destination)) + mapping.Extension
+		}
+		if err := validateManifestPath(destination); err != nil {
+			return nil, fmt.Errorf("destination for %s: %w", rel, err)
+		}
+
+		expanded = append(expanded, expandedMapping{source: file, destination: destination})
+	}
+
+	return expanded, nil
+}
+
+func listFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan source directory %q: %w", root, err)
+	}
+
+	return files, nil
+}
+
+func matchesRuleFilters(rel string, include []string, exclude []string) bool {
+	if len(include) > 0 {
+		included := false
+		for _, pattern := range include {
+			if matchFilterPattern(pattern, rel) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+
+	for _, pattern := range exclude {
+		if matchFilterPattern(pattern, rel) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchFilterPattern(pattern string, rel string) bool {
+	target := rel
+	if !strings.Contains(pattern, "/") {
+		target = path.Base(rel)
+	}
+
+	matched, err := path.Match(pattern, target)
+	return err == nil && matched
+}
+
+func hasGlobMeta(value string) bool {
+	return strings.ContainsAny(value, "*?[")
+}
+
+func globStaticPrefix(pattern string) string {
+	parts := strings.Split(filepath.ToSlash(pattern), "/")
+	prefix := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if hasGlobMeta(part) {
+			break
+		}
+		prefix = append(prefix, part)
+	}
+	return strings.Join(prefix, "/")
+}
+
+func ruleApplies(condition *RuleCondition, ctx ProjectionContext) (bool, error) {
+	if condition == nil {
+		return true, nil
+	}
+
+	if condition.Profile != "" && condition.Profile != ctx.Profile {
+		return false, nil
+	}
+
+	if condition.AgentRuntime != "" {
+		runtimes := ctx.AgentRuntimes
+		if len(runtimes) == 0 {
+			runtimes = []string{DefaultAgentRuntime}
+		}
+
+		matched := false
+		for _, runtime := range runtimes {
+			if runtime == condition.AgentRuntime {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+
+	if condition.FileExists != "" {
+		if ctx.ProjectRoot == "" {
+			return false, nil
+		}
+		exists, err := pathExists(filepath.Join(ctx.ProjectRoot, filepath.FromSlash(condition.FileExists)))
+		if err != nil {
+			return false, err
+		}
+		if !exists {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 func policyForMode(mapping ProjectRootRule, mode ProjectionMode) (CopyPolicy, error) {
 	switch mode {
 	case ModeInit:
@@ -0,0 +1,200 @@
+package scaffold
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBuildProjectRootActions_ExpandsGlobAndDirectoryRules(t *testing.T) {
+	t.Parallel()
+
+	sourceRoot := t.TempDir()
+	writeTestFile(t, filepath.Join(sourceRoot, "subagents", "planner.md"), "x")
+	writeTestFile(t, filepath.Join(sourceRoot, "subagents", "verifier.md"), "x")
+	writeTestFile(t, filepath.Join(sourceRoot, "subagents", "notes.txt"), "x")
+	writeTestFile(t, filepath.Join(sourceRoot, "skills", "auditor", "SKILL.md"), "x")
+	writeTestFile(t, filepath.Join(sourceRoot, "skills", "auditor", "DRAFT.md"), "x")
+	writeTestFile(t, filepath.Join(sourceRoot, "skills", "verification.md"), "x")
+
+	manifest := ProjectRootManifest{
+		Version: CurrentManifestVersion,
+		Mappings: []ProjectRootRule{
+			{Source: "subagents/*.md", Destination: ".opencode/agents/", OnInit: PolicyIfMissing, OnUpdate: PolicyIfMissing},
+			{Source: "skills", Destination: ".opencode/skills", OnInit: PolicyIfMissing, OnUpdate: PolicyIfMissing, Exclude: []string{"DRAFT.md"}},
+		},
+	}
+
+	actions, err := BuildProjectRootActions(manifest, sourceRoot, ModeInit, ProjectionContext{})
+	if err != nil {
+		t.Fatalf("build actions: %v", err)
+	}
+
+	got := make([]string, 0, len(actions))
+	for _, action := range actions {
+		rel, err := filepath.Rel(sourceRoot, action.Source)
+		if err != nil {
+			t.Fatalf("rel: %v", err)
+		}
+		got = append(got, filepath.ToSlash(rel)+" -> "+filepath.ToSlash(action.Destination))
+	}
+
+	want := []string{
+		"subagents/planner.md -> .opencode/agents/planner.md",
+		"subagents/verifier.md -> .opencode/agents/verifier.md",
+		"skills/auditor/SKILL.md -> .opencode/skills/auditor/SKILL.md",
+		"skills/verification.md -> .opencode/skills/verification.md",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("actions:\ngot  %v\nwant %v", got, want)
+	}
+}
+
+func TestBuildProjectRootActions_IncludeFilterAndExplicitOverride(t *testing.T) {
+	t.Parallel()
+
+	sourceRoot := t.TempDir()
+	writeTestFile(t, filepath.Join(sourceRoot, "agents", "a.md"), "x")
+	writeTestFile(t, filepath.Join(sourceRoot, "agents", "b.json"), "x")
+	writeTestFile(t, filepath.Join(sourceRoot, "custom", "a.md"), "x")
+
+	manifest := ProjectRootManifest{
+		Version: CurrentManifestVersion,
+		Mappings: []ProjectRootRule{
+			{Source: "agents", Destination: ".opencode/agents", OnInit: PolicyIfMissing, OnUpdate: PolicyAlwaysOverwrite, Include: []string{"*.md"}},
+			{Source: "custom/a.md", Destination: ".opencode/agents/a.md", OnInit: PolicyIfMissing, OnUpdate: PolicyNeverOverwrite},
+		},
+	}
+
+	actions, err := BuildProjectRootActions(manifest, sourceRoot, ModeUpdate, ProjectionContext{})
+	if err != nil {
+		t.Fatalf("build actions: %v", err)
+	}
+
+	if len(actions) != 1 {
+		t.Fatalf("actions: got %d, want 1: %+v", len(actions), actions)
+	}
+	if actions[0].Source != filepath.Join(sourceRoot, "custom", "a.md") {
+		t.Fatalf("source: got %q", actions[0].Source)
+	}
+	if actions[0].Policy != PolicyNeverOverwrite {
+		t.Fatalf("policy: got %q", actions[0].Policy)
+	}
+}
+
+func TestBuildProjectRootActions_RejectsBadFilterPattern(t *testing.T) {
+	t.Parallel()
+
+	manifest := ProjectRootManifest{
+		Version: CurrentManifestVersion,
+		Mappings: []ProjectRootRule{
+			{Source: "agents", Destination: ".opencode/agents", OnInit: PolicyIfMissing, OnUpdate: PolicyIfMissing, Include: []string{"[md"}},
+		},
+	}
+
+	if _, err := BuildProjectRootActions(manifest, t.TempDir(), ModeInit, ProjectionContext{}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestBuildProjectRootActions_BundledManifest(t *testing.T) {
+	t.Parallel()
+
+	sourceRoot := filepath.Join("..", "..", "methodology")
+	manifest, err := LoadProjectRootManifest(filepath.Join(sourceRoot, "project_root", "manifest.json"))
+	if err != nil {
+		t.Fatalf("load bundled manifest: %v", err)
+	}
+
+	actions, err := BuildProjectRootActions(manifest, sourceRoot, ModeInit, ProjectionContext{})
+	if err != nil {
+		t.Fatalf("build actions: %v", err)
+	}
+
+	destinations := map[string]bool{}
+	for _, action := range actions {
+		destinations[filepath.ToSlash(action.Destination)] = true
+	}
+
+	for _, want := range []string{"AGENTS.md", "opencode.json", ".opencode/agents/featureplanner.md", ".opencode/agents/productengineer.md"} {
+		if !destinations[want] {
+			t.Fatalf("bundled manifest does not project %s; got %v", want, destinations)
+		}
+	}
+	// build-feature.md is a primary agent with broad edit rights; projects opt in by hand.
+	if destinations[".opencode/agents/build-feature.md"] {
+		t.Fatalf("bundled manifest projects .opencode/agents/build-feature.md; got %v", destinations)
+	}
+}
+
+func TestBuildProjectRootActions_Targets(t *testing.T) {
+	t.Parallel()
+
+	sourceRoot := filepath.Join("..", "..", "methodology")
+	manifest, err := LoadProjectRootManifest(filepath.Join(sourceRoot, "project_root", "manifest.json"))
+	if err != nil {
+		t.Fatalf("load bundled manifest: %v", err)
+	}
+
+	actions, err := BuildProjectRootActions(manifest, sourceRoot, ModeUpdate, ProjectionContext{AgentRuntimes: []string{"claude", "cursor"}})
+	if err != nil {
+		t.Fatalf("build actions: %v", err)
+	}
+
+	destinations := map[string]ProjectionAction{}
+	for _, action := range actions {
+		destinations[filepath.ToSlash(action.Destination)] = action
+	}
+
+	for _, want := range []string{"AGENTS.md", "CLAUDE.md", ".claude/agents/verifier.md", ".claude/skills/spec-auditor/SKILL.md", ".cursor/rules/verifier.mdc"} {
+		if _, ok := destinations[want]; !ok {
+			t.Fatalf("targets do not project %s; got %v", want, destinations)
+		}
+	}
+	if _, ok := destinations["opencode.json"]; ok {
+		t.Fatal("opencode target should not be projected when not selected")
+	}
+	if destinations[".claude/agents/verifier.md"].Frontmatter == nil {
+		t.Fatal("claude agent should carry a frontmatter transform")
+	}
+
+	if _, err := BuildProjectRootActions(manifest, sourceRoot, ModeUpdate, ProjectionContext{AgentRuntimes: []string{"emacs"}}); err == nil {
+		t.Fatal("expected unknown target error, got nil")
+	}
+}
+
+func TestRuleApplies(t *testing.T) {
+	t.Parallel()
+
+	projectRoot := t.TempDir()
+	writeTestFile(t, filepath.Join(projectRoot, "go.mod"), "module demo\n")
+
+	tests := []struct {
+		name      string
+		condition *RuleCondition
+		ctx       ProjectionContext
+		want      bool
+	}{
+		{name: "no condition", condition: nil, want: true},
+		{name: "profile match", condition: &RuleCondition{Profile: "backend"}, ctx: ProjectionContext{Profile: "backend"}, want: true},
+		{name: "profile mismatch", condition: &RuleCondition{Profile: "backend"}, ctx: ProjectionContext{Profile: "docs"}, want: false},
+		{name: "profile unset", condition: &RuleCondition{Profile: "backend"}, want: false},
+		{name: "file exists", condition: &RuleCondition{FileExists: "go.mod"}, ctx: ProjectionContext{ProjectRoot: projectRoot}, want: true},
+		{name: "file missing", condition: &RuleCondition{FileExists: "package.json"}, ctx: ProjectionContext{ProjectRoot: projectRoot}, want: false},
+		{name: "default runtime", condition: &RuleCondition{AgentRuntime: "opencode"}, want: true},
+		{name: "runtime mismatch", condition: &RuleCondition{AgentRuntime: "cursor"}, ctx: ProjectionContext{AgentRuntimes: []string{"opencode"}}, want: false},
+		{name: "all fields", condition: &RuleCondition{Profile: "backend", FileExists: "go.mod", AgentRuntime: "opencode"}, ctx: ProjectionContext{ProjectRoot: projectRoot, Profile: "backend"}, want: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ruleApplies(tc.condition, tc.ctx)
+			if err != nil {
+				t.Fatalf("ruleApplies: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("ruleApplies: got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
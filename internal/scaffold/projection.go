@@ -0,0 +1,555 @@
+package scaffold
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"opencode-spire/internal/methodology"
+)
+
+type RenderVars struct {
+	ProjectName string
+	Profile     string
+}
+
+type projector struct {
+	projectRoot string
+	mode        ProjectionMode
+	vars        RenderVars
+	ledger      *projectionLedger
+	upstream    map[string]string
+	out         io.Writer
+	refreshed   []string
+	customized  []string
+	backups     []projectionBackup
+	saved       map[string]bool
+}
+
+type projectionBackup struct {
+	path    string
+	existed bool
+	content []byte
+	mode    os.FileMode
+}
+
+type projectionLedger struct {
+	methodologyDir string
+	hashes         map[string]string
+	merged         map[string]bool
+	mergeSources   map[string]json.RawMessage
+}
+
+func newProjector(projectRoot string, methodologyDir string, mode ProjectionMode, out io.Writer) (*projector, []ProjectionAction, error) {
+	manifestPath := filepath.Join(methodologyDir, "project_root", "manifest.json")
+	manifest, err := LoadProjectRootManifest(manifestPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	settings, err := methodology.ReadProjectSettings(projectRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx := ProjectionContext{ProjectRoot: projectRoot, Profile: settings.Profile, AgentRuntimes: settings.Targets}
+	actions, err := BuildProjectRootActions(manifest, methodologyDir, mode, ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ledger, err := loadProjectionLedger(methodologyDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &projector{
+		projectRoot: projectRoot,
+		mode:        mode,
+		vars:        ProjectRenderVars(projectRoot, settings.Profile),
+		ledger:      ledger,
+		out:         out,
+		saved:       map[string]bool{},
+	}, actions, nil
+}
+
+// UpstreamHashes hashes what the payload in methodologyDir projects to each
+// whole-file destination. Update takes them before the sync replaces the
+// payload, so a destination the ledger has no record of (an install older than
+// the ledger) still counts as unmodified when it matches the old upstream.
+// Destinations whose content cannot be produced are left out and reported in
+// the returned error alongside the hashes that could be taken.
+func UpstreamHashes(projectRoot string, methodologyDir string) (map[string]string, error) {
+	p, actions, err := newProjector(projectRoot, methodologyDir, ModeUpdate, io.Discard)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := map[string]string{}
+	var errs []error
+	for _, action := range actions {
+		if action.Source == "" || action.Policy == PolicyJSONMerge || action.Policy == PolicyManagedBlock {
+			continue
+		}
+
+		content, err := p.desiredContent(action)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		hashes[filepath.ToSlash(action.Destination)] = contentHash(content)
+	}
+
+	return hashes, errors.Join(errs...)
+}
+
+func ProjectRenderVars(projectRoot string, profile string) RenderVars {
+	return RenderVars{
+		ProjectName: filepath.Base(filepath.Clean(projectRoot)),
+		Profile:     profile,
+	}
+}
+
+func RenderProjectionTemplate(source string, vars RenderVars) ([]byte, error) {
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("read template %q: %w", source, err)
+	}
+
+	tmpl, err := template.New(filepath.Base(source)).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse template %q: %w", source, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return nil, fmt.Errorf("render template %q: %w", source, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (p *projector) desiredContent(action ProjectionAction) ([]byte, error) {
+	if action.Policy == PolicyRender {
+		return RenderProjectionTemplate(action.Source, p.vars)
+	}
+
+	data, err := os.ReadFile(action.Source)
+	if err != nil {
+		return nil, fmt.Errorf("open source file %q: %w", action.Source, err)
+	}
+
+	if action.Frontmatter == nil {
+		return data, nil
+	}
+
+	name := filepath.Base(action.Destination)
+	vars := FrontmatterVars{Name: strings.TrimSuffix(name, filepath.Ext(name)), ProjectName: p.vars.ProjectName}
+	transformed, err := TransformFrontmatter(data, *action.Frontmatter, vars)
+	if err != nil {
+		return nil, fmt.Errorf("transform %q: %w", action.Source, err)
+	}
+	return transformed, nil
+}
+
+func (p *projector) overwrite(action ProjectionAction, destination string, exists bool) error {
+	desired, err := p.desiredContent(action)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		current, err := os.ReadFile(destination)
+		if err != nil {
+			return fmt.Errorf("read destination file %q: %w", destination, err)
+		}
+		if bytes.Equal(current, desired) {
+			p.ledger.record(action.Destination, desired)
+			return nil
+		}
+	}
+
+	return p.write(action, destination, desired, exists)
+}
+
+func (p *projector) overwriteIfUnmodified(action ProjectionAction, destination string, exists bool) error {
+	desired, err := p.desiredContent(action)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return p.write(action, destination, desired, false)
+	}
+
+	current, err := os.ReadFile(destination)
+	if err != nil {
+		return fmt.Errorf("read destination file %q: %w", destination, err)
+	}
+
+	if bytes.Equal(current, desired) {
+		p.ledger.record(action.Destination, desired)
+		return nil
+	}
+
+	if p.mode == ModeInit {
+		fmt.Fprintf(p.out, "skipped existing: %s\n", action.Destination)
+		return nil
+	}
+
+	if p.unmodified(action.Destination, current) {
+		p.refreshed = append(p.refreshed, action.Destination)
+		return p.write(action, destination, desired, true)
+	}
+
+	stat := diffStat(current, desired)
+	p.customized = append(p.customized, fmt.Sprintf("%s (%s)", action.Destination, stat))
+	fmt.Fprintf(p.out, "notice: %s was modified locally; kept existing (%s)\n", action.Destination, stat)
+	return nil
+}
+
+func (p *projector) noticeKept(action ProjectionAction, destination string, sourceRel string) error {
+	current, err := os.ReadFile(destination)
+	if err != nil {
+		return fmt.Errorf("read destination file %q: %w", destination, err)
+	}
+
+	recorded, ok := p.ledger.lookup(action.Destination)
+	if !ok || recorded == contentHash(current) {
+		fmt.Fprintf(p.out, "notice: upstream %s changed; kept existing %s\n", sourceRel, action.Destination)
+		return nil
+	}
+
+	desired, err := p.desiredContent(action)
+	if err != nil {
+		return err
+	}
+
+	stat := diffStat(current, desired)
+	p.customized = append(p.customized, fmt.Sprintf("%s (%s)", action.Destination, stat))
+	fmt.Fprintf(p.out, "notice: upstream %s changed; kept existing %s (customized, %s)\n", sourceRel, action.Destination, stat)
+	return nil
+}
+
+func (p *projector) copy(action ProjectionAction, destination string) error {
+	if err := p.snapshot(destination); err != nil {
+		return err
+	}
+
+	if action.Frontmatter != nil {
+		desired, err := p.desiredContent(action)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(destination), 0o755); err != nil {
+			return fmt.Errorf("create destination parent %q: %w", filepath.Dir(destination), err)
+		}
+		if err := os.WriteFile(destination, desired, 0o644); err != nil {
+			return fmt.Errorf("write destination file %q: %w", destination, err)
+		}
+		p.ledger.record(action.Destination, desired)
+		return nil
+	}
+
+	if err := copyFile(action.Source, destination); err != nil {
+		return err
+	}
+	if action.Policy == PolicyJSONMerge {
+		if err := p.ledger.recordMergeSource(action); err != nil {
+			return err
+		}
+	}
+	return p.recordDestination(action, destination)
+}
+
+func (p *projector) mergeJSON(action ProjectionAction, destination string) error {
+	if err := p.snapshot(destination); err != nil {
+		return err
+	}
+
+	mergedKeys, err := MergeJSONFile(action.Source, destination, p.ledger.mergeSource(action.Destination))
+	if err != nil {
+		return err
+	}
+	if err := p.ledger.recordMergeSource(action); err != nil {
+		return err
+	}
+
+	if len(mergedKeys) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(p.out, "merged: %s (%s)\n", action.Destination, strings.Join(mergedKeys, ", "))
+	if _, ok := p.ledger.lookup(action.Destination); !ok {
+		p.ledger.markMerged(action.Destination)
+	}
+	return p.recordDestination(action, destination)
+}
+
+func (p *projector) managedBlock(action ProjectionAction, destination string, exists bool, insert bool) error {
+	if err := p.snapshot(destination); err != nil {
+		return err
+	}
+
+	wrote, err := projectManagedBlock(action, destination, exists, insert, p.out)
+	if err != nil || !wrote {
+		return err
+	}
+	return p.recordDestination(action, destination)
+}
+
+func (p *projector) recordDestination(action ProjectionAction, destination string) error {
+	content, err := os.ReadFile(destination)
+	if err != nil {
+		return fmt.Errorf("read destination file %q: %w", destination, err)
+	}
+	p.ledger.record(action.Destination, content)
+	return nil
+}
+
+func (p *projector) report() {
+	if len(p.refreshed) > 0 {
+		fmt.Fprintln(p.out, "refreshed untouched files:")
+		for _, path := range p.refreshed {
+			fmt.Fprintf(p.out, "- %s\n", path)
+		}
+	}
+
+	if len(p.customized) > 0 {
+		fmt.Fprintln(p.out, "kept customized files:")
+		for _, path := range p.customized {
+			fmt.Fprintf(p.out, "- %s\n", path)
+		}
+	}
+}
+
+func (p *projector) removeRetired(action ProjectionAction, destination string, exists bool) error {
+	if !exists {
+		p.ledger.forget(action.Destination)
+		return nil
+	}
+
+	current, err := os.ReadFile(destination)
+	if err != nil {
+		return fmt.Errorf("read destination file %q: %w", destination, err)
+	}
+
+	unmodified := p.unmodified(action.Destination, current)
+	if !unmodified && action.Source != "" {
+		if source, err := os.ReadFile(action.Source); err == nil && bytes.Equal(source, current) {
+			unmodified = true
+		}
+	}
+
+	if !unmodified {
+		fmt.Fprintf(p.out, "notice: retired %s was modified locally; kept existing\n", action.Destination)
+		return nil
+	}
+
+	if err := p.snapshot(destination); err != nil {
+		return err
+	}
+	if err := os.Remove(destination); err != nil {
+		return fmt.Errorf("remove retired file %q: %w", destination, err)
+	}
+	p.ledger.forget(action.Destination)
+
+	fmt.Fprintf(p.out, "removed: %s\n", action.Destination)
+	return nil
+}
+
+func (p *projector) write(action ProjectionAction, destination string, content []byte, exists bool) error {
+	if err := p.snapshot(destination); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destination), 0o755); err != nil {
+		return fmt.Errorf("create destination parent %q: %w", filepath.Dir(destination), err)
+	}
+
+	if err := os.WriteFile(destination, content, 0o644); err != nil {
+		return fmt.Errorf("write destination file %q: %w", destination, err)
+	}
+	p.ledger.record(action.Destination, content)
+
+	if exists {
+		fmt.Fprintf(p.out, "updated: %s\n", action.Destination)
+	} else {
+		fmt.Fprintf(p.out, "created: %s\n", action.Destination)
+	}
+	return nil
+}
+
+// unmodified reports whether current is what spire last wrote to destination.
+// Without a ledger entry it falls back to the previous payload's content.
+func (p *projector) unmodified(destination string, current []byte) bool {
+	if recorded, ok := p.ledger.lookup(destination); ok {
+		return recorded == contentHash(current)
+	}
+	hash, ok := p.upstream[filepath.ToSlash(destination)]
+	return ok && hash == contentHash(current)
+}
+
+// snapshot remembers what destination held before this run first touched it,
+// so rollback can put it back.
+func (p *projector) snapshot(destination string) error {
+	if p.saved[destination] {
+		return nil
+	}
+
+	backup := projectionBackup{path: destination}
+	info, err := os.Stat(destination)
+	switch {
+	case err == nil:
+		content, err := os.ReadFile(destination)
+		if err != nil {
+			return fmt.Errorf("read destination file %q: %w", destination, err)
+		}
+		backup.existed = true
+		backup.content = content
+		backup.mode = info.Mode().Perm()
+	case !os.IsNotExist(err):
+		return fmt.Errorf("stat %q: %w", destination, err)
+	}
+
+	p.saved[destination] = true
+	p.backups = append(p.backups, backup)
+	return nil
+}
+
+// rollback restores every destination touched so far, newest first, and
+// returns cause unchanged.
+func (p *projector) rollback(cause error) error {
+	for i := len(p.backups) - 1; i >= 0; i-- {
+		backup := p.backups[i]
+		if !backup.existed {
+			if err := os.Remove(backup.path); err == nil {
+				removeEmptyParents(p.projectRoot, filepath.Dir(backup.path))
+			}
+			continue
+		}
+		_ = os.WriteFile(backup.path, backup.content, backup.mode)
+	}
+
+	p.backups = nil
+	p.saved = map[string]bool{}
+	return cause
+}
+
+func loadProjectionLedger(methodologyDir string) (*projectionLedger, error) {
+	hashes, err := methodology.ReadProjectionHashes(methodologyDir)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := methodology.ReadMergedProjections(methodologyDir)
+	if err != nil {
+		return nil, err
+	}
+
+	mergeSources, err := methodology.ReadMergeSources(methodologyDir)
+	if err != nil {
+		return nil, err
+	}
+	if mergeSources == nil {
+		mergeSources = map[string]json.RawMessage{}
+	}
+
+	ledger := &projectionLedger{methodologyDir: methodologyDir, hashes: hashes, merged: map[string]bool{}, mergeSources: mergeSources}
+	for _, destination := range merged {
+		ledger.merged[destination] = true
+	}
+	return ledger, nil
+}
+
+func (l *projectionLedger) lookup(destination string) (string, bool) {
+	hash, ok := l.hashes[filepath.ToSlash(destination)]
+	return hash, ok
+}
+
+func (l *projectionLedger) record(destination string, content []byte) {
+	l.hashes[filepath.ToSlash(destination)] = contentHash(content)
+}
+
+// markMerged records that destination existed before spire first wrote to it.
+func (l *projectionLedger) markMerged(destination string) {
+	l.merged[filepath.ToSlash(destination)] = true
+}
+
+func (l *projectionLedger) wasMerged(destination string) bool {
+	return l.merged[filepath.ToSlash(destination)]
+}
+
+// recordMergeSource remembers the upstream document merged into a json_merge
+// destination; the next merge only adds what upstream added since.
+func (l *projectionLedger) recordMergeSource(action ProjectionAction) error {
+	data, err := os.ReadFile(action.Source)
+	if err != nil {
+		return fmt.Errorf("read source file %q: %w", action.Source, err)
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		// Merging reports malformed upstream JSON; there is nothing to keep.
+		return nil
+	}
+	l.mergeSources[filepath.ToSlash(action.Destination)] = compact.Bytes()
+	return nil
+}
+
+func (l *projectionLedger) mergeSource(destination string) []byte {
+	return l.mergeSources[filepath.ToSlash(destination)]
+}
+
+func (l *projectionLedger) forget(destination string) {
+	delete(l.hashes, filepath.ToSlash(destination))
+	delete(l.merged, filepath.ToSlash(destination))
+	delete(l.mergeSources, filepath.ToSlash(destination))
+}
+
+func (l *projectionLedger) save() error {
+	var merged []string
+	for destination := range l.merged {
+		merged = append(merged, destination)
+	}
+	sort.Strings(merged)
+	return methodology.WriteProjectionHashes(l.methodologyDir, l.hashes, merged, l.mergeSources)
+}
+
+// diffStat summarizes how current differs from upstream as added/removed line
+// counts. It compares line multisets, which is enough for a one-line notice.
+func diffStat(current []byte, upstream []byte) string {
+	counts := map[string]int{}
+	for _, line := range strings.Split(string(upstream), "\n") {
+		counts[line]++
+	}
+
+	added := 0
+	for _, line := range strings.Split(string(current), "\n") {
+		if counts[line] > 0 {
+			counts[line]--
+			continue
+		}
+		added++
+	}
+
+	removed := 0
+	for _, remaining := range counts {
+		removed += remaining
+	}
+
+	return fmt.Sprintf("+%d -%d lines vs upstream", added, removed)
+}
+
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,50 @@
+package scaffold
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDiffStat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		current  string
+		upstream string
+		want     string
+	}{
+		{name: "identical", current: "a\nb\n", upstream: "a\nb\n", want: "+0 -0 lines vs upstream"},
+		{name: "added line", current: "a\nlocal\nb\n", upstream: "a\nb\n", want: "+1 -0 lines vs upstream"},
+		{name: "replaced line", current: "a\nlocal\n", upstream: "a\nb\n", want: "+1 -1 lines vs upstream"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := diffStat([]byte(tc.current), []byte(tc.upstream)); got != tc.want {
+				t.Fatalf("diffStat: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRenderProjectionTemplate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	source := filepath.Join(dir, "template.md")
+	writeTestFile(t, source, "# {{ .ProjectName }}\n")
+
+	got, err := RenderProjectionTemplate(source, RenderVars{ProjectName: "demo"})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if string(got) != "# demo\n" {
+		t.Fatalf("render: got %q", string(got))
+	}
+
+	writeTestFile(t, source, "{{ .Missing }}\n")
+	if _, err := RenderProjectionTemplate(source, RenderVars{}); err == nil {
+		t.Fatal("expected error for unknown variable, got nil")
+	}
+}
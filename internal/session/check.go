@@ -0,0 +1,116 @@
+package session
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultMaxAge is how old `Last updated` may be before check calls it stale.
+const DefaultMaxAge = 7 * 24 * time.Hour
+
+var placeholderPattern = regexp.MustCompile(`\[[^\]\[]+\]|YYYY-MM-DD`)
+
+type Issue struct {
+	Line    int
+	Message string
+}
+
+func (i Issue) String() string {
+	if i.Line == 0 {
+		return i.Message
+	}
+	return fmt.Sprintf("line %d: %s", i.Line, i.Message)
+}
+
+// Check reports continuity problems in a parsed SESSION.md: missing template
+// sections, unfilled placeholders, a stale or missing `Last updated`, completed
+// items without commit refs, an empty Next Action, and Failure Log entries that
+// have tripped the circuit breaker.
+func Check(s Session, now time.Time, maxAge time.Duration) []Issue {
+	var issues []Issue
+
+	for _, name := range SectionNames {
+		if _, ok := s.Section(name); !ok {
+			issues = append(issues, Issue{Message: fmt.Sprintf("missing section: ## %s", name)})
+		}
+	}
+
+	for _, line := range s.lines {
+		if placeholder := findPlaceholder(line.Text); placeholder != "" {
+			issues = append(issues, Issue{Line: line.Number, Message: fmt.Sprintf("unfilled placeholder %s", placeholder)})
+		}
+	}
+
+	switch {
+	case s.LastUpdatedLine == 0:
+		issues = append(issues, Issue{Message: "missing Last updated line"})
+	case s.LastUpdated.IsZero():
+		if findPlaceholder(s.LastUpdatedText) == "" {
+			issues = append(issues, Issue{Line: s.LastUpdatedLine, Message: fmt.Sprintf("Last updated %q is not a YYYY-MM-DD [HH:MM] date", s.LastUpdatedText)})
+		}
+	case maxAge > 0 && now.Sub(s.LastUpdated) > maxAge:
+		age := now.Sub(s.LastUpdated).Truncate(time.Hour)
+		issues = append(issues, Issue{Line: s.LastUpdatedLine, Message: fmt.Sprintf("Last updated %s is stale (%s old)", s.LastUpdatedText, formatAge(age))})
+	}
+
+	for _, item := range s.Completed {
+		if item.Commit == "" {
+			issues = append(issues, Issue{Line: item.Line, Message: fmt.Sprintf("completed item has no commit ref: %s", item.Text)})
+		}
+	}
+
+	if section, ok := s.Section(SectionNextAction); ok && len(section.Content()) == 0 {
+		issues = append(issues, Issue{Line: section.Line, Message: "Next Action is empty"})
+	}
+
+	issues = append(issues, CheckEscalations(s)...)
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Line < issues[j].Line })
+	return issues
+}
+
+// WorkRecorded reports whether the Completed or In Progress section lists an
+// item that is not a template slot, so a freshly scaffolded SESSION.md does
+// not count as started work.
+func (s Session) WorkRecorded() bool {
+	for _, name := range []string{SectionCompleted, SectionInProgress} {
+		section, ok := s.Section(name)
+		if !ok {
+			continue
+		}
+		for _, item := range section.Items() {
+			text := strings.ToLower(strings.Trim(item.Text, " .-"))
+			if text != "" && text != "none" && text != "n/a" && findPlaceholder(item.Text) == "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findPlaceholder returns the first template slot in text, such as
+// `[task description]` or `YYYY-MM-DD`. Checkboxes and markdown links are not
+// slots.
+func findPlaceholder(text string) string {
+	for _, loc := range placeholderPattern.FindAllStringIndex(text, -1) {
+		match := text[loc[0]:loc[1]]
+		if match == "[ ]" || match == "[x]" || match == "[X]" {
+			continue
+		}
+		if loc[1] < len(text) && text[loc[1]] == '(' {
+			continue
+		}
+		return match
+	}
+	return ""
+}
+
+func formatAge(age time.Duration) string {
+	if days := int(age.Hours() / 24); days >= 1 {
+		return fmt.Sprintf("%dd", days)
+	}
+	return age.String()
+}
@@ -0,0 +1,117 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const filledSession = `# Session Log: Retry downloads
+Last updated: 2026-03-10 14:30 | Agent: sonnet
+
+## Status
+Overall: 60%
+Current phase: T3
+
+## Completed (with commit refs)
+- T1 add backoff helper → commit abc1234
+- T2 retry on 5xx
+
+## In Progress
+- T3 resume downloads
+
+## Closed Decisions (do not re-litigate)
+- Retry budget: three attempts → decided in session 2026-03-09
+
+## Discovered Constraints (not in original spec)
+- None so far
+
+## Failure Log (circuit-breaker)
+
+## Next Action
+Resume from the Range header handling in download.go.
+`
+
+func TestParseReadsHeaderSectionsAndCompletedItems(t *testing.T) {
+	parsed, err := Parse(strings.NewReader(filledSession))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	if parsed.Title != "Retry downloads" || parsed.Agent != "sonnet" || parsed.Overall != "60%" {
+		t.Fatalf("header: %+v", parsed)
+	}
+	if want := time.Date(2026, 3, 10, 14, 30, 0, 0, time.Local); !parsed.LastUpdated.Equal(want) {
+		t.Fatalf("LastUpdated: got %v, want %v", parsed.LastUpdated, want)
+	}
+	if len(parsed.Sections) != len(SectionNames) {
+		t.Fatalf("sections: %+v", parsed.Sections)
+	}
+	for i, name := range SectionNames {
+		if parsed.Sections[i].Name != name {
+			t.Fatalf("section %d: got %q, want %q", i, parsed.Sections[i].Name, name)
+		}
+	}
+	if len(parsed.Completed) != 2 || parsed.Completed[0].Commit != "abc1234" || parsed.Completed[1].Commit != "" {
+		t.Fatalf("completed: %+v", parsed.Completed)
+	}
+	if section, _ := parsed.Section(SectionInProgress); len(section.Items()) != 1 {
+		t.Fatalf("in progress items: %+v", section.Items())
+	}
+}
+
+func TestCheckReportsContinuityGaps(t *testing.T) {
+	parsed, err := Parse(strings.NewReader(filledSession))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	now := time.Date(2026, 3, 20, 9, 0, 0, 0, time.Local)
+	var got []string
+	for _, issue := range Check(parsed, now, DefaultMaxAge) {
+		got = append(got, issue.String())
+	}
+
+	want := []string{
+		"line 2: Last updated 2026-03-10 14:30 is stale (9d old)",
+		"line 10: completed item has no commit ref: T2 retry on 5xx",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if issues := Check(parsed, now, 0); len(issues) != 1 {
+		t.Fatalf("max age 0 should disable staleness: %v", issues)
+	}
+}
+
+func TestCheckFlagsUntouchedTemplate(t *testing.T) {
+	template := "# Session Log: [Feature Name]\nLast updated: YYYY-MM-DD HH:MM | Agent: [model used]\n\n## Status\nOverall: [% complete estimate]\n\n## Completed (with commit refs)\n- [ ] wire retries\n- see [docs](README.md) → commit abc1234\n\n## Next Action\n\n"
+	parsed, err := Parse(strings.NewReader(template))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	var got []string
+	for _, issue := range Check(parsed, time.Now(), DefaultMaxAge) {
+		got = append(got, issue.String())
+	}
+	joined := strings.Join(got, "\n")
+
+	for _, want := range []string{
+		"missing section: ## In Progress",
+		"missing section: ## Failure Log",
+		"line 1: unfilled placeholder [Feature Name]",
+		"line 2: unfilled placeholder YYYY-MM-DD",
+		"line 5: unfilled placeholder [% complete estimate]",
+		"line 8: completed item has no commit ref: [ ] wire retries",
+		"line 11: Next Action is empty",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Fatalf("issues missing %q:\n%s", want, joined)
+		}
+	}
+	if strings.Contains(joined, "[docs]") || strings.Contains(joined, "placeholder [ ]") || strings.Contains(joined, "not a YYYY-MM-DD") {
+		t.Fatalf("unexpected issue:\n%s", joined)
+	}
+}
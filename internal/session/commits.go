@@ -0,0 +1,83 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"opencode-spire/internal/git"
+	"opencode-spire/internal/tasks"
+)
+
+var completedTaskPattern = regexp.MustCompile(`^\*{0,2}([Tt]\d+)\b`)
+
+// VerifyCommits checks each `→ commit <sha>` in the Completed section against
+// repo: the commit must exist, be reachable from HEAD, and touch at least one
+// of the files listed for its task in TASKS.md. list may be nil.
+func VerifyCommits(ctx context.Context, repo git.Repo, s Session, list *tasks.List) ([]Issue, error) {
+	var issues []Issue
+	for _, item := range s.Completed {
+		if item.Commit == "" {
+			continue
+		}
+
+		commit, err := repo.ResolveCommit(ctx, item.Commit)
+		if errors.Is(err, git.ErrUnknownCommit) {
+			issues = append(issues, Issue{Line: item.Line, Message: fmt.Sprintf("commit %s does not exist", item.Commit)})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		reachable, err := repo.IsAncestor(ctx, commit, "HEAD")
+		if err != nil {
+			return nil, err
+		}
+		if !reachable {
+			issues = append(issues, Issue{Line: item.Line, Message: fmt.Sprintf("commit %s is not reachable from HEAD (rebased or on another branch?)", item.Commit)})
+			continue
+		}
+
+		task, ok := completedTask(item, list)
+		if !ok || len(task.Files) == 0 {
+			continue
+		}
+		changed, err := repo.ChangedFiles(ctx, commit)
+		if err != nil {
+			return nil, err
+		}
+		if !touchesAny(changed, task.Files) {
+			issues = append(issues, Issue{Line: item.Line, Message: fmt.Sprintf("commit %s touches none of %s's files (%s)", item.Commit, task.ID, strings.Join(task.Files, ", "))})
+		}
+	}
+	return issues, nil
+}
+
+func completedTask(item CompletedItem, list *tasks.List) (tasks.Task, bool) {
+	if list == nil {
+		return tasks.Task{}, false
+	}
+	match := completedTaskPattern.FindStringSubmatch(item.Text)
+	if match == nil {
+		return tasks.Task{}, false
+	}
+	return list.Find(match[1])
+}
+
+// touchesAny reports whether a changed file is one of files, or lies under one
+// of them when it names a directory.
+func touchesAny(changed []string, files []string) bool {
+	for _, file := range changed {
+		for _, want := range files {
+			want = strings.TrimSuffix(path.Clean(strings.TrimPrefix(want, "./")), "/")
+			if file == want || strings.HasPrefix(file, want+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package session
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"opencode-spire/internal/git"
+	"opencode-spire/internal/tasks"
+)
+
+func TestVerifyCommitsFlagsFabricatedUnreachableAndUnrelated(t *testing.T) {
+	repo := &git.Fake{Head: "cccc333000", Commits: map[string]git.FakeCommit{
+		"aaaa111000": {Files: []string{"internal/retry/backoff.go"}},
+		"bbbb222000": {Parents: []string{"aaaa111000"}, Files: []string{"README.md"}},
+		"cccc333000": {Parents: []string{"bbbb222000"}, Files: []string{"internal/retry/resume.go"}},
+		"dddd444000": {Parents: []string{"aaaa111000"}, Files: []string{"internal/retry/client.go"}},
+	}}
+	list, err := tasks.Parse(strings.NewReader("- [x] T1: backoff\n  - Files: internal/retry/backoff.go\n- [x] T2: client\n  - Files: internal/retry/client.go\n- [x] T3: docs\n  - Files: docs/retry.md\n- [x] T4: resume\n  - Files: internal/retry/\n"))
+	if err != nil {
+		t.Fatalf("tasks.Parse: %v", err)
+	}
+	parsed, err := Parse(strings.NewReader("## Completed\n- T1 backoff → commit aaaa111\n- T2 client → commit dddd444\n- T3 docs → commit bbbb222\n- T4 resume → commit cccc333\n- T5 cache → commit eeee555\n- T6 notes\n"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	issues, err := VerifyCommits(context.Background(), repo, parsed, &list)
+	if err != nil {
+		t.Fatalf("VerifyCommits: %v", err)
+	}
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		"line 3: commit dddd444 is not reachable from HEAD (rebased or on another branch?)",
+		"line 4: commit bbbb222 touches none of T3's files (docs/retry.md)",
+		"line 6: commit eeee555 does not exist",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,90 @@
+package session
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// EscalationThreshold is the failure count at which SC-3 says to stop and
+// bring in a human.
+const EscalationThreshold = 3
+
+var failedCountPattern = regexp.MustCompile(`(?i)failed\s+\[?(\d+)\]?\s*(?:times?|×|x)(?:\W|$)`)
+
+type Failure struct {
+	Step  string
+	Count int
+	Text  string
+	Line  int
+}
+
+func (f Failure) Escalated() bool {
+	return f.Count >= EscalationThreshold
+}
+
+func (f Failure) String() string {
+	return fmt.Sprintf("%s failed %d×", f.Step, f.Count)
+}
+
+// Failures reads the `<step>: failed N times` entries of the Failure Log.
+// Entries whose count is still a template slot are skipped.
+func (s Session) Failures() []Failure {
+	section, ok := s.Section(SectionFailureLog)
+	if !ok {
+		return nil
+	}
+
+	var failures []Failure
+	for _, item := range section.Items() {
+		match := failedCountPattern.FindStringSubmatch(item.Text)
+		if match == nil {
+			continue
+		}
+		count, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		failures = append(failures, Failure{Step: failureStep(item.Text), Count: count, Text: item.Text, Line: item.Number})
+	}
+	return failures
+}
+
+// Escalations returns the failures that have reached EscalationThreshold.
+func (s Session) Escalations() []Failure {
+	var escalations []Failure
+	for _, failure := range s.Failures() {
+		if failure.Escalated() {
+			escalations = append(escalations, failure)
+		}
+	}
+	return escalations
+}
+
+// CheckEscalations reports each Failure Log entry that has tripped the
+// circuit breaker.
+func CheckEscalations(s Session) []Issue {
+	var issues []Issue
+	for _, failure := range s.Escalations() {
+		issues = append(issues, Issue{
+			Line:    failure.Line,
+			Message: fmt.Sprintf("escalation: %s (circuit breaker trips at %d)", failure, EscalationThreshold),
+		})
+	}
+	return issues
+}
+
+func failureStep(text string) string {
+	step := text
+	if before, _, found := strings.Cut(text, ":"); found {
+		step = before
+	} else if loc := failedCountPattern.FindStringIndex(text); loc != nil {
+		step = text[:loc[0]]
+	}
+	step = strings.Trim(strings.TrimSpace(step), "`*")
+	if step == "" {
+		return "step"
+	}
+	return step
+}
@@ -0,0 +1,27 @@
+package session
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFailuresReadsCountsAndEscalations(t *testing.T) {
+	log := "## Failure Log (circuit-breaker)\n- auth test: failed 4 times. Approach tried: mock clock. ESCALATE if N ≥ 3.\n- `go vet`: failed 1 time.\n- flaky upload failed 3× in CI\n- [test/step name]: failed [N] times. Approach tried: [...].\n- note without a count\n\n## Next Action\nAsk about the clock.\n"
+	parsed, err := Parse(strings.NewReader(log))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	var got []string
+	for _, failure := range parsed.Failures() {
+		got = append(got, failure.String())
+	}
+	if want := "auth test failed 4×|go vet failed 1×|flaky upload failed 3×"; strings.Join(got, "|") != want {
+		t.Fatalf("failures: got %q, want %q", strings.Join(got, "|"), want)
+	}
+
+	issues := CheckEscalations(parsed)
+	if len(issues) != 2 || issues[0].String() != "line 2: escalation: auth test failed 4× (circuit breaker trips at 3)" || issues[1].Line != 4 {
+		t.Fatalf("escalations: %v", issues)
+	}
+}
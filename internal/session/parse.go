@@ -0,0 +1,210 @@
+package session
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Section names from .methodology/templates/session-template.md, in order.
+const (
+	SectionStatus      = "Status"
+	SectionCompleted   = "Completed"
+	SectionInProgress  = "In Progress"
+	SectionDecisions   = "Closed Decisions"
+	SectionConstraints = "Discovered Constraints"
+	SectionFailureLog  = "Failure Log"
+	SectionNextAction  = "Next Action"
+)
+
+var SectionNames = []string{
+	SectionStatus,
+	SectionCompleted,
+	SectionInProgress,
+	SectionDecisions,
+	SectionConstraints,
+	SectionFailureLog,
+	SectionNextAction,
+}
+
+var (
+	lastUpdatedPattern = regexp.MustCompile(`^Last updated:\s*([^|]*?)\s*(?:\|\s*Agent:\s*(.*))?$`)
+	commitRefPattern   = regexp.MustCompile(`commit\s+([0-9a-fA-F]{7,40})\b`)
+)
+
+var lastUpdatedLayouts = []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"}
+
+type Line struct {
+	Number int
+	Text   string
+}
+
+type Section struct {
+	Name    string
+	Heading string
+	Line    int
+	Lines   []Line
+}
+
+type CompletedItem struct {
+	Text   string
+	Commit string
+	Line   int
+}
+
+type Session struct {
+	Title string
+	// LastUpdatedText is the raw value after `Last updated:`; LastUpdated is
+	// zero when it is missing or does not parse.
+	LastUpdatedText string
+	LastUpdated     time.Time
+	LastUpdatedLine int
+	Agent           string
+	Overall         string
+	Sections        []Section
+	Completed       []CompletedItem
+	lines           []Line
+}
+
+// Load parses a feature's SESSION.md. It returns nil when the file does not
+// exist.
+func Load(projectRoot string, slug string) (*Session, error) {
+	path := Path(projectRoot, slug)
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("open session file %q: %w", path, err)
+	}
+	defer file.Close()
+
+	parsed, err := Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &parsed, nil
+}
+
+// Parse splits SESSION.md into its `## ` sections and reads the header,
+// progress line and completed items. Unknown sections are kept as-is.
+func Parse(r io.Reader) (Session, error) {
+	var parsed Session
+	current := -1
+	lineNumber := 0
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineNumber++
+		text := scanner.Text()
+		trimmed := strings.TrimSpace(text)
+		parsed.lines = append(parsed.lines, Line{Number: lineNumber, Text: text})
+
+		switch {
+		case strings.HasPrefix(trimmed, "## "):
+			heading := strings.TrimSpace(strings.TrimPrefix(trimmed, "## "))
+			parsed.Sections = append(parsed.Sections, Section{Name: canonicalSection(heading), Heading: heading, Line: lineNumber})
+			current = len(parsed.Sections) - 1
+			continue
+		case strings.HasPrefix(trimmed, "# ") && parsed.Title == "":
+			parsed.Title = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(trimmed, "# "), "Session Log:"))
+			continue
+		}
+
+		if match := lastUpdatedPattern.FindStringSubmatch(trimmed); match != nil && parsed.LastUpdatedLine == 0 {
+			parsed.LastUpdatedText = match[1]
+			parsed.LastUpdatedLine = lineNumber
+			parsed.Agent = strings.TrimSpace(match[2])
+			parsed.LastUpdated = parseLastUpdated(match[1])
+		}
+		if parsed.Overall == "" {
+			for _, prefix := range []string{"Overall:", "Status:"} {
+				if strings.HasPrefix(trimmed, prefix) {
+					parsed.Overall = strings.TrimSpace(strings.TrimPrefix(trimmed, prefix))
+				}
+			}
+		}
+
+		if current < 0 {
+			continue
+		}
+		section := &parsed.Sections[current]
+		section.Lines = append(section.Lines, Line{Number: lineNumber, Text: text})
+
+		if section.Name == SectionCompleted && isListItem(text) {
+			item := CompletedItem{Text: listItemText(text), Line: lineNumber}
+			if match := commitRefPattern.FindStringSubmatch(trimmed); match != nil {
+				item.Commit = strings.ToLower(match[1])
+			}
+			parsed.Completed = append(parsed.Completed, item)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Session{}, fmt.Errorf("scan session: %w", err)
+	}
+
+	return parsed, nil
+}
+
+// Section returns the first section with the given canonical name.
+func (s Session) Section(name string) (Section, bool) {
+	for _, section := range s.Sections {
+		if section.Name == name {
+			return section, true
+		}
+	}
+	return Section{}, false
+}
+
+// Items returns the section's top-level list items without their markers.
+func (s Section) Items() []Line {
+	var items []Line
+	for _, line := range s.Lines {
+		if isListItem(line.Text) {
+			items = append(items, Line{Number: line.Number, Text: listItemText(line.Text)})
+		}
+	}
+	return items
+}
+
+// Content returns the section's non-blank lines.
+func (s Section) Content() []Line {
+	var content []Line
+	for _, line := range s.Lines {
+		if strings.TrimSpace(line.Text) != "" {
+			content = append(content, line)
+		}
+	}
+	return content
+}
+
+func canonicalSection(heading string) string {
+	lower := strings.ToLower(heading)
+	for _, name := range SectionNames {
+		if strings.HasPrefix(lower, strings.ToLower(name)) {
+			return name
+		}
+	}
+	return heading
+}
+
+func parseLastUpdated(value string) time.Time {
+	for _, layout := range lastUpdatedLayouts {
+		if parsed, err := time.ParseInLocation(layout, strings.TrimSpace(value), time.Local); err == nil {
+			return parsed
+		}
+	}
+	return time.Time{}
+}
+
+func isListItem(line string) bool {
+	return strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ")
+}
+
+func listItemText(line string) string {
+	return strings.TrimSpace(line[2:])
+}
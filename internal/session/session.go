@@ -0,0 +1,73 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	Filename         = "SESSION.md"
+	completedHeading = "## Completed"
+)
+
+// Path returns where a feature's session log lives.
+func Path(projectRoot string, slug string) string {
+	return filepath.Join(projectRoot, "changes", slug, Filename)
+}
+
+// AppendCompleted adds entry as a list item at the end of the Completed
+// section, creating the section at the end of the file when it is missing.
+// An entry that is already listed is not added again, so a retried command
+// does not record the same commit twice.
+func AppendCompleted(path string, entry string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read session file %q: %w", path, err)
+	}
+
+	item := "- " + strings.TrimSpace(entry)
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+
+	start := -1
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), completedHeading) {
+			start = i
+			break
+		}
+	}
+
+	if start < 0 {
+		lines = append(lines, "", completedHeading+" (with commit refs)", item)
+	} else {
+		end := len(lines)
+		for i := start + 1; i < len(lines); i++ {
+			if strings.HasPrefix(lines[i], "#") {
+				end = i
+				break
+			}
+		}
+
+		insert := start + 1
+		for i := end - 1; i > start; i-- {
+			if strings.TrimSpace(lines[i]) != "" {
+				insert = i + 1
+				break
+			}
+		}
+
+		for _, line := range lines[start+1 : end] {
+			if strings.TrimSpace(line) == item {
+				return nil
+			}
+		}
+
+		lines = append(lines[:insert], append([]string{item}, lines[insert:]...)...)
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		return fmt.Errorf("write session file %q: %w", path, err)
+	}
+	return nil
+}
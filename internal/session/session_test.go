@@ -0,0 +1,57 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAppendCompleted(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "after last entry",
+			content: "## Completed (with commit refs)\n- T1 a → commit 1111111\n\n## In Progress\n- T2\n",
+			want:    "## Completed (with commit refs)\n- T1 a → commit 1111111\n- T2 b → commit 2222222\n\n## In Progress\n- T2\n",
+		},
+		{
+			name:    "empty section",
+			content: "# Log\n\n## Completed (with commit refs)\n\n## Next Action\nx\n",
+			want:    "# Log\n\n## Completed (with commit refs)\n- T2 b → commit 2222222\n\n## Next Action\nx\n",
+		},
+		{
+			name:    "already listed",
+			content: "## Completed (with commit refs)\n- T2 b → commit 2222222\n\n## In Progress\n",
+			want:    "## Completed (with commit refs)\n- T2 b → commit 2222222\n\n## In Progress\n",
+		},
+		{
+			name:    "missing section",
+			content: "# Log\n",
+			want:    "# Log\n\n## Completed (with commit refs)\n- T2 b → commit 2222222\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), Filename)
+			if err := os.WriteFile(path, []byte(tc.content), 0o644); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+
+			if err := AppendCompleted(path, "T2 b → commit 2222222"); err != nil {
+				t.Fatalf("AppendCompleted: %v", err)
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if string(got) != tc.want {
+				t.Fatalf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
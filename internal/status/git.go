@@ -0,0 +1,117 @@
+package status
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"opencode-spire/internal/git"
+)
+
+var baseBranches = []string{"main", "master"}
+
+// GitState is what git history says about a feature.
+type GitState struct {
+	Branch string
+	// Ahead counts the branch's commits that are not on the base branch.
+	Ahead        int
+	Merged       bool
+	LastActivity time.Time
+}
+
+// InspectGit looks for a branch named after the feature (`003-retry` or
+// `feature/003-retry`), compares it with main (or master), and reads the last
+// commit on HEAD that touches changes/<slug>.
+func InspectGit(ctx context.Context, repo git.Repo, slug string) (GitState, error) {
+	var state GitState
+
+	branches, err := repo.Branches(ctx)
+	if err != nil {
+		return GitState{}, err
+	}
+	base := ""
+	for _, candidate := range baseBranches {
+		if contains(branches, candidate) {
+			base = candidate
+			break
+		}
+	}
+	for _, branch := range branches {
+		if branch == slug || strings.HasSuffix(branch, "/"+slug) {
+			state.Branch = branch
+			break
+		}
+	}
+
+	if state.Branch != "" && base != "" {
+		if state.Ahead, err = repo.CommitsAhead(ctx, state.Branch, base); err != nil {
+			return GitState{}, err
+		}
+		if state.Ahead == 0 {
+			if state.Merged, err = mergedInto(ctx, repo, state.Branch, base); err != nil {
+				return GitState{}, err
+			}
+		}
+	}
+
+	if state.LastActivity, err = repo.LastCommitTime(ctx, path.Join("changes", slug)); err != nil {
+		return GitState{}, err
+	}
+	return state, nil
+}
+
+// mergedInto reports whether branch was merged into base. A branch whose tip
+// is still the base tip was only just created, so it does not count.
+func mergedInto(ctx context.Context, repo git.Repo, branch string, base string) (bool, error) {
+	branchTip, err := repo.ResolveCommit(ctx, branch)
+	if err != nil {
+		return false, err
+	}
+	baseTip, err := repo.ResolveCommit(ctx, base)
+	if err != nil {
+		return false, err
+	}
+	if branchTip == baseTip {
+		return false, nil
+	}
+	return repo.IsAncestor(ctx, branch, base)
+}
+
+// WithGit refines a file-based state with git history: a verified feature whose
+// branch is already merged is no longer waiting for a PR.
+func WithGit(state string, gitState GitState) string {
+	if state == "Awaiting PR" && gitState.Merged {
+		return "Merged, not archived"
+	}
+	return state
+}
+
+// BranchSummary renders the branch column, such as `feature/003-retry (+4)`.
+func (g GitState) BranchSummary() string {
+	switch {
+	case g.Branch == "":
+		return "-"
+	case g.Merged:
+		return g.Branch + " (merged)"
+	default:
+		return fmt.Sprintf("%s (+%d)", g.Branch, g.Ahead)
+	}
+}
+
+func (g GitState) LastActivitySummary() string {
+	if g.LastActivity.IsZero() {
+		return "-"
+	}
+	return g.LastActivity.Local().Format("2006-01-02")
+}
+
+func contains(values []string, want string) bool {
+	for _, value := range values {
+		if value == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package status
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"opencode-spire/internal/git"
+)
+
+func TestInspectGitReadsBranchesAndActivity(t *testing.T) {
+	day := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	repo := &git.Fake{
+		Head: "m2",
+		Refs: map[string]string{"main": "m2", "feature/001-alpha": "a2", "002-beta": "b1", "004-delta": "m2"},
+		Commits: map[string]git.FakeCommit{
+			"m1": {Files: []string{"README.md"}, Time: day},
+			"a1": {Parents: []string{"m1"}, Files: []string{"changes/001-alpha/SESSION.md"}, Time: day.Add(24 * time.Hour)},
+			"a2": {Parents: []string{"a1"}, Files: []string{"main.go"}, Time: day.Add(48 * time.Hour)},
+			"b1": {Parents: []string{"m1"}, Files: []string{"changes/002-beta/VERIFICATION_REPORT.md"}, Time: day.Add(72 * time.Hour)},
+			"m2": {Parents: []string{"m1", "b1"}, Time: day.Add(96 * time.Hour)},
+		},
+	}
+	ctx := context.Background()
+
+	alpha, err := InspectGit(ctx, repo, "001-alpha")
+	if err != nil {
+		t.Fatalf("InspectGit alpha: %v", err)
+	}
+	if alpha.BranchSummary() != "feature/001-alpha (+2)" || alpha.Merged || !alpha.LastActivity.IsZero() {
+		t.Fatalf("alpha: %+v", alpha)
+	}
+	if got := WithGit("Awaiting PR", alpha); got != "Awaiting PR" {
+		t.Fatalf("alpha state: %q", got)
+	}
+
+	beta, err := InspectGit(ctx, repo, "002-beta")
+	if err != nil {
+		t.Fatalf("InspectGit beta: %v", err)
+	}
+	if beta.BranchSummary() != "002-beta (merged)" || !beta.LastActivity.Equal(day.Add(72*time.Hour)) {
+		t.Fatalf("beta: %+v", beta)
+	}
+	if got := WithGit("Awaiting PR", beta); got != "Merged, not archived" {
+		t.Fatalf("beta state: %q", got)
+	}
+
+	gamma, err := InspectGit(ctx, repo, "003-gamma")
+	if err != nil {
+		t.Fatalf("InspectGit gamma: %v", err)
+	}
+	if gamma.BranchSummary() != "-" || gamma.LastActivitySummary() != "-" {
+		t.Fatalf("gamma: %+v", gamma)
+	}
+
+	delta, err := InspectGit(ctx, repo, "004-delta")
+	if err != nil {
+		t.Fatalf("InspectGit delta: %v", err)
+	}
+	if delta.Merged || delta.BranchSummary() != "004-delta (+0)" {
+		t.Fatalf("a branch just created from main is not merged: %+v", delta)
+	}
+}
@@ -1,11 +1,15 @@
 package status
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"opencode-spire/internal/approval"
+	"opencode-spire/internal/session"
+	"opencode-spire/internal/tasks"
 )
 
 func Infer(projectRoot string, slug string) (string, error) {
@@ -27,23 +31,39 @@ func Infer(projectRoot string, slug string) (string, error) {
 		return "Awaiting PR", nil
 	}
 
-	if exists, err := pathExists(sessionFile); err != nil {
+	sessionExists, err := pathExists(sessionFile)
+	if err != nil {
 		return "", err
-	} else if exists {
-		progress, err := parseSessionProgress(sessionFile)
+	}
+	planExists, err := pathExists(planFile)
+	if err != nil {
+		return "", err
+	}
+
+	// Approval decides the state until work starts: spire new scaffolds
+	// SESSION.md long before that. Work that started without an approved plan
+	// shows its progress with a warning.
+	if planExists {
+		approved, stale, err := planApproval(projectRoot, slug)
 		if err != nil {
 			return "", err
 		}
-		if progress == "" {
-			return "In progress", nil
+		started, err := workStarted(projectRoot, slug)
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case !started && approved:
+			return "Approved", nil
+		case !started:
+			return "Plan awaiting approval" + staleNote(stale), nil
+		case !approved:
+			return inProgressState(projectRoot, slug, "plan not approved"+staleNote(stale))
 		}
-		return fmt.Sprintf("In progress (%s)", progress), nil
 	}
 
-	if exists, err := pathExists(planFile); err != nil {
-		return "", err
-	} else if exists {
-		return "Awaiting implementation", nil
+	if sessionExists || planExists {
+		return inProgressState(projectRoot, slug, "")
 	}
 
 	if exists, err := pathExists(specAudit); err != nil {
@@ -55,29 +75,92 @@ func Infer(projectRoot string, slug string) (string, error) {
 	return "Spec only", nil
 }
 
-func parseSessionProgress(path string) (string, error) {
-	file, err := os.Open(path)
+// inProgressState derives progress from the checkboxes in TASKS.md, falling
+// back to the self-reported Status/Overall line in SESSION.md when there is no
+// task list, and flags warning (when set) and Failure Log entries that have
+// tripped the circuit breaker.
+func inProgressState(projectRoot string, slug string, warning string) (string, error) {
+	parsed, err := session.Load(projectRoot, slug)
 	if err != nil {
-		return "", fmt.Errorf("open session file %q: %w", path, err)
+		return "", err
+	}
+	list, err := tasks.Load(projectRoot, slug)
+	var invalid *tasks.ValidationError
+	if err != nil && !errors.As(err, &invalid) {
+		return "", err
+	}
+
+	state := "In progress"
+	switch {
+	case invalid != nil:
+		state += fmt.Sprintf(" (%s %v)", tasks.Filename, invalid)
+	case list != nil && len(list.Tasks) > 0:
+		state += fmt.Sprintf(" (%s)", list.Progress())
+	case parsed != nil && parsed.Overall != "":
+		state += fmt.Sprintf(" (%s)", parsed.Overall)
+	}
+	if warning != "" {
+		state += " ⚠ " + warning
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if strings.HasPrefix(line, "Status:") {
-			return strings.TrimSpace(strings.TrimPrefix(line, "Status:")), nil
+	if parsed == nil {
+		return state, nil
+	}
+	var escalations []string
+	for _, failure := range parsed.Escalations() {
+		escalations = append(escalations, failure.String())
+	}
+	if len(escalations) > 0 {
+		state += " ⚠ escalation: " + strings.Join(escalations, ", ")
+	}
+	return state, nil
+}
+
+// workStarted reports whether a task is checked or SESSION.md records work
+// under Completed or In Progress.
+func workStarted(projectRoot string, slug string) (bool, error) {
+	list, err := tasks.Load(projectRoot, slug)
+	var invalid *tasks.ValidationError
+	if err != nil && !errors.As(err, &invalid) {
+		return false, err
+	}
+	if list != nil && list.Progress().Done > 0 {
+		return true, nil
+	}
+
+	parsed, err := session.Load(projectRoot, slug)
+	if err != nil {
+		return false, err
+	}
+	return parsed != nil && parsed.WorkRecorded(), nil
+}
+
+// planApproval reports whether PLAN.md and TASKS.md both carry a Gate 2
+// sign-off that still matches them, and which files changed since theirs.
+func planApproval(projectRoot string, slug string) (bool, []string, error) {
+	statuses, err := approval.Status(projectRoot, slug)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var stale []string
+	approved := true
+	for _, status := range statuses {
+		if status.State == approval.Stale {
+			stale = append(stale, status.File)
 		}
-		if strings.HasPrefix(line, "Overall:") {
-			return strings.TrimSpace(strings.TrimPrefix(line, "Overall:")), nil
+		if status.State != approval.Approved {
+			approved = false
 		}
 	}
+	return approved, stale, nil
+}
 
-	if err := scanner.Err(); err != nil {
-		return "", fmt.Errorf("scan session file %q: %w", path, err)
+func staleNote(stale []string) string {
+	if len(stale) == 0 {
+		return ""
 	}
-
-	return "", nil
+	return fmt.Sprintf(" (%s changed since approval)", strings.Join(stale, ", "))
 }
 
 func pathExists(path string) (bool, error) {
@@ -4,6 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"opencode-spire/internal/approval"
 )
 
 func TestInferStatuses(t *testing.T) {
@@ -29,7 +32,7 @@ func TestInferStatuses(t *testing.T) {
 	}{
 		{slug: "001-spec-only", want: "Spec only"},
 		{slug: "002-awaiting-planning", want: "Awaiting planning"},
-		{slug: "003-awaiting-implementation", want: "Awaiting implementation"},
+		{slug: "003-awaiting-implementation", want: "Plan awaiting approval"},
 		{slug: "004-in-progress", want: "In progress (task 2/5)"},
 		{slug: "005-awaiting-pr", want: "Awaiting PR"},
 		{slug: "006-complete", want: "Complete"},
@@ -62,6 +65,85 @@ func TestParseSessionStatusLine(t *testing.T) {
 	}
 }
 
+func TestInferProgressComesFromTasks(t *testing.T) {
+	projectRoot := t.TempDir()
+	write(t, filepath.Join(projectRoot, "specs", "feature-001-x.md"), "x")
+	write(t, filepath.Join(projectRoot, "changes", "001-x", "SESSION.md"), "## Status\nOverall: 90% done\n")
+	write(t, filepath.Join(projectRoot, "changes", "001-x", "TASKS.md"), "- [x] T1: parse config\n- [ ] T2: add retry\n  - Depends on: T1\n- [ ] T3: wire flag\n")
+
+	got, err := Infer(projectRoot, "001-x")
+	if err != nil {
+		t.Fatalf("Infer error: %v", err)
+	}
+	if got != "In progress (1/3 tasks, next: T2 add retry)" {
+		t.Fatalf("status: got %q", got)
+	}
+}
+
+func TestInferShowsInvalidTasksInRow(t *testing.T) {
+	projectRoot := t.TempDir()
+	write(t, filepath.Join(projectRoot, "specs", "feature-001-x.md"), "x")
+	write(t, filepath.Join(projectRoot, "changes", "001-x", "SESSION.md"), "## Status\nOverall: 90% done\n")
+	write(t, filepath.Join(projectRoot, "changes", "001-x", "TASKS.md"), "- [ ] T1: a\n- [ ] T1: b\n")
+
+	got, err := Infer(projectRoot, "001-x")
+	if err != nil {
+		t.Fatalf("Infer error: %v", err)
+	}
+	if got != "In progress (TASKS.md line 2: duplicate task id T1 (first on line 1))" {
+		t.Fatalf("status: got %q", got)
+	}
+}
+
+func TestInferFlagsCircuitBreakerEscalations(t *testing.T) {
+	projectRoot := t.TempDir()
+	write(t, filepath.Join(projectRoot, "specs", "feature-001-x.md"), "x")
+	write(t, filepath.Join(projectRoot, "changes", "001-x", "SESSION.md"), "## Status\nOverall: 40%\n\n## Failure Log (circuit-breaker)\n- auth test: failed 4 times. Approach tried: mock clock.\n- lint: failed 2 times.\n- [test/step name]: failed [N] times. ESCALATE if N ≥ 3.\n")
+
+	got, err := Infer(projectRoot, "001-x")
+	if err != nil {
+		t.Fatalf("Infer error: %v", err)
+	}
+	if got != "In progress (40%) ⚠ escalation: auth test failed 4×" {
+		t.Fatalf("status: got %q", got)
+	}
+}
+
+func TestInferPlanApproval(t *testing.T) {
+	projectRoot := t.TempDir()
+	write(t, filepath.Join(projectRoot, "specs", "feature-001-x.md"), "x")
+	write(t, filepath.Join(projectRoot, "changes", "001-x", "PLAN.md"), "# Plan\n")
+	write(t, filepath.Join(projectRoot, "changes", "001-x", "TASKS.md"), "- [ ] T1: parse config\n")
+
+	infer := func() string {
+		t.Helper()
+		got, err := Infer(projectRoot, "001-x")
+		if err != nil {
+			t.Fatalf("Infer error: %v", err)
+		}
+		return got
+	}
+
+	if _, err := approval.Approve(projectRoot, "001-x", []string{"PLAN.md"}, "Ada", time.Now()); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if got := infer(); got != "Plan awaiting approval" {
+		t.Fatalf("plan only: got %q", got)
+	}
+
+	if _, err := approval.Approve(projectRoot, "001-x", []string{"TASKS.md"}, "Ada", time.Now()); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if got := infer(); got != "Approved" {
+		t.Fatalf("both approved: got %q", got)
+	}
+
+	write(t, filepath.Join(projectRoot, "changes", "001-x", "PLAN.md"), "# Plan\nMore scope.\n")
+	if got := infer(); got != "Plan awaiting approval (PLAN.md changed since approval)" {
+		t.Fatalf("edited plan: got %q", got)
+	}
+}
+
 func write(t *testing.T, path string, content string) {
 	t.Helper()
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
@@ -71,3 +153,48 @@ func write(t *testing.T, path string, content string) {
 		t.Fatalf("write %s: %v", path, err)
 	}
 }
+
+func TestInferChecksApprovalBeforeProgress(t *testing.T) {
+	projectRoot := t.TempDir()
+	write(t, filepath.Join(projectRoot, "specs", "feature-001-x.md"), "x")
+	write(t, filepath.Join(projectRoot, "changes", "001-x", "PLAN.md"), "# Plan\n")
+	write(t, filepath.Join(projectRoot, "changes", "001-x", "TASKS.md"), "- [ ] T1: parse config\n- [ ] T2: add retry\n")
+	write(t, filepath.Join(projectRoot, "changes", "001-x", "SESSION.md"), "## Completed (with commit refs)\n- [task description] → commit abc1234\n")
+
+	infer := func() string {
+		t.Helper()
+		got, err := Infer(projectRoot, "001-x")
+		if err != nil {
+			t.Fatalf("Infer error: %v", err)
+		}
+		return got
+	}
+
+	if got := infer(); got != "Plan awaiting approval" {
+		t.Fatalf("scaffolded session: got %q", got)
+	}
+
+	write(t, filepath.Join(projectRoot, "changes", "001-x", "TASKS.md"), "- [x] T1: parse config\n- [ ] T2: add retry\n")
+	if got := infer(); got != "In progress (1/2 tasks, next: T2 add retry) ⚠ plan not approved" {
+		t.Fatalf("unapproved work: got %q", got)
+	}
+
+	write(t, filepath.Join(projectRoot, "changes", "001-x", "SESSION.md"), "## Failure Log (circuit-breaker)\n- retry test: failed 3 times.\n")
+	if got := infer(); got != "In progress (1/2 tasks, next: T2 add retry) ⚠ plan not approved ⚠ escalation: retry test failed 3×" {
+		t.Fatalf("unapproved work with escalation: got %q", got)
+	}
+
+	write(t, filepath.Join(projectRoot, "changes", "001-x", "TASKS.md"), "- [ ] T1: parse config\n- [ ] T2: add retry\n")
+	write(t, filepath.Join(projectRoot, "changes", "001-x", "SESSION.md"), "## Completed (with commit refs)\n- [task description] → commit abc1234\n")
+	if _, err := approval.Approve(projectRoot, "001-x", approval.Files, "Ada", time.Now()); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if got := infer(); got != "Approved" {
+		t.Fatalf("approved, not started: got %q", got)
+	}
+
+	write(t, filepath.Join(projectRoot, "changes", "001-x", "SESSION.md"), "## In Progress\n- T1 parse config\n")
+	if got := infer(); got != "In progress (0/2 tasks, next: T1 parse config)" {
+		t.Fatalf("approved and started: got %q", got)
+	}
+}
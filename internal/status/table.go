@@ -6,6 +6,10 @@ type Row struct {
 	Number  string
 	Feature string
 	Status  string
+	// Branch and LastActivity are filled in from git; the table only shows
+	// them when some row has them.
+	Branch       string
+	LastActivity string
 }
 
 func RenderTable(rows []Row) string {
@@ -13,7 +17,23 @@ func RenderTable(rows []Row) string {
 		return ""
 	}
 
+	withGit := false
+	for _, row := range rows {
+		if row.Branch != "" || row.LastActivity != "" {
+			withGit = true
+		}
+	}
+
 	output := ""
+	if withGit {
+		output += fmt.Sprintf("%-5s %-35s %-30s %-13s %s\n", "#", "Feature", "Branch", "Last activity", "Status")
+		output += fmt.Sprintf("%-5s %-35s %-30s %-13s %s\n", "---", "-----------------------------------", "------------------------------", "-------------", "------")
+		for _, row := range rows {
+			output += fmt.Sprintf("%-5s %-35s %-30s %-13s %s\n", row.Number, row.Feature, row.Branch, row.LastActivity, row.Status)
+		}
+		return output
+	}
+
 	output += fmt.Sprintf("%-5s %-35s %s\n", "#", "Feature", "Status")
 	output += fmt.Sprintf("%-5s %-35s %s\n", "---", "-----------------------------------", "------")
 	for _, row := range rows {
@@ -0,0 +1,62 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// MarkDone checks the box of task id in the TASKS.md at path, leaving every
+// other byte of the file alone. It reports whether the task was already done.
+func MarkDone(path string, id string) (Task, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Task{}, false, fmt.Errorf("read tasks file %q: %w", path, err)
+	}
+
+	list, err := Parse(strings.NewReader(string(data)))
+	if err != nil {
+		return Task{}, false, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	task, ok := list.Find(id)
+	if !ok {
+		return Task{}, false, fmt.Errorf("unknown task %s", strings.ToUpper(strings.TrimSpace(id)))
+	}
+	if task.Done {
+		return task, true, nil
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	switch {
+	case !task.heading:
+		lines[task.Line-1] = strings.Replace(lines[task.Line-1], "[ ]", "[x]", 1)
+	case task.statusLine > 0:
+		lines[task.statusLine-1] = statusDoneLine(lines[task.statusLine-1])
+	default:
+		if !strings.HasSuffix(lines[task.Line-1], "\n") {
+			lines[task.Line-1] += "\n"
+		}
+		lines[task.Line-1] += "**Status:** done\n"
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "")), 0o644); err != nil {
+		return Task{}, false, fmt.Errorf("write tasks file %q: %w", path, err)
+	}
+
+	task.Done = true
+	return task, false, nil
+}
+
+// statusDoneLine rewrites the value of a `**Status:**` line to done, keeping
+// its key and line ending.
+func statusDoneLine(line string) string {
+	ending := ""
+	if strings.HasSuffix(line, "\n") {
+		ending = "\n"
+	}
+	key, _, _ := strings.Cut(strings.TrimSuffix(line, ending), ":")
+	if strings.HasPrefix(strings.TrimSpace(key), "**") && !strings.HasSuffix(key, "**") {
+		return key + ":** done" + ending
+	}
+	return key + ": done" + ending
+}
@@ -0,0 +1,288 @@
+package tasks
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const Filename = "TASKS.md"
+
+var (
+	checkboxPattern = regexp.MustCompile(`^ ?[-*+]\s+\[([ xX])\]\s*(.*)$`)
+	headingPattern  = regexp.MustCompile(`^#{2,6}\s+(?:[Tt]ask\s+|[Tt])(\d+)\s*(?:[:.)\-–—]\s*|\s+|$)(.*)$`)
+	anyHeading      = regexp.MustCompile(`^#{1,6}\s`)
+	ruleLine        = regexp.MustCompile(`^\s*(?:-{3,}|\*{3,}|_{3,})\s*$`)
+	listItemPattern = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	headingField    = regexp.MustCompile(`^\s*\*{0,2}([A-Za-z][A-Za-z ]*?)\*{0,2}\s*:\*{0,2}\s*(.*)$`)
+	taskIDPattern   = regexp.MustCompile(`^\*{0,2}[Tt](\d+)\*{0,2}\s*(?:[:.)\-–—]\s*|\s+|$)(.*)$`)
+	fieldPattern    = regexp.MustCompile(`^\s+(?:[-*+]\s+)?\*{0,2}([A-Za-z][A-Za-z ]*?)\*{0,2}\s*:\*{0,2}\s*(.*)$`)
+	dependsPattern  = regexp.MustCompile(`\b(?:[Tt]ask\s+|[Tt])(\d+)`)
+)
+
+type Task struct {
+	ID           string
+	Title        string
+	Done         bool
+	Goal         string
+	Files        []string
+	Tests        string
+	Verification string
+	DependsOn    []string
+	// Line is the 1-based line of the task's checkbox or heading in TASKS.md.
+	Line int
+
+	// heading is set for tasks written as `### Task <n> — title` sections,
+	// which record completion in a `**Status:**` line instead of a checkbox.
+	heading    bool
+	statusLine int
+}
+
+type List struct {
+	Tasks []Task
+}
+
+type Progress struct {
+	Done  int
+	Total int
+	// Next is the first unchecked task whose dependencies are all checked.
+	Next *Task
+}
+
+// Path returns where a feature's task list lives.
+func Path(projectRoot string, slug string) string {
+	return filepath.Join(projectRoot, "changes", slug, Filename)
+}
+
+// Load parses a feature's TASKS.md. It returns nil when the file does not exist.
+func Load(projectRoot string, slug string) (*List, error) {
+	path := Path(projectRoot, slug)
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("open tasks file %q: %w", path, err)
+	}
+	defer file.Close()
+
+	list, err := Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &list, nil
+}
+
+// Parse reads tasks in either of two forms: top-level checkbox items, with
+// indented `Key: value` lines filling in the goal, files, tests, verification
+// and dependencies; or `### Task <n> — title` sections, whose `**Key:**`
+// lines may be followed by list items continuing that field and whose
+// `**Status:** done` line marks completion. Anything else is ignored.
+// Checkbox tasks without a `T<n>` id are numbered by position.
+func Parse(r io.Reader) (List, error) {
+	var list List
+	current := -1
+	lastKey := ""
+	lineNumber := 0
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+
+		if match := headingPattern.FindStringSubmatch(line); match != nil {
+			number, _ := strconv.Atoi(match[1])
+			task := Task{ID: fmt.Sprintf("T%d", number), Title: strings.TrimSpace(match[2]), Line: lineNumber, heading: true}
+			list.Tasks = append(list.Tasks, task)
+			current = len(list.Tasks) - 1
+			lastKey = ""
+			continue
+		}
+		if current >= 0 && list.Tasks[current].heading {
+			if anyHeading.MatchString(line) || ruleLine.MatchString(line) {
+				current = -1
+				continue
+			}
+			if match := headingField.FindStringSubmatch(line); match != nil && !listItemPattern.MatchString(line) {
+				lastKey = match[1]
+				setField(&list.Tasks[current], lastKey, strings.TrimSpace(match[2]))
+				if isStatusKey(lastKey) {
+					list.Tasks[current].statusLine = lineNumber
+				}
+				continue
+			}
+			if match := listItemPattern.FindStringSubmatch(line); match != nil && lastKey != "" {
+				setField(&list.Tasks[current], lastKey, strings.TrimSpace(match[1]))
+			}
+			continue
+		}
+
+		if match := checkboxPattern.FindStringSubmatch(line); match != nil {
+			task := Task{Done: match[1] != " ", Line: lineNumber}
+			task.ID, task.Title = splitTaskID(strings.TrimSpace(match[2]), len(list.Tasks)+1)
+			list.Tasks = append(list.Tasks, task)
+			current = len(list.Tasks) - 1
+			continue
+		}
+
+		if current < 0 {
+			continue
+		}
+		if strings.TrimSpace(line) != "" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			current = -1
+			continue
+		}
+
+		if match := fieldPattern.FindStringSubmatch(line); match != nil {
+			setField(&list.Tasks[current], match[1], strings.TrimSpace(match[2]))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return List{}, fmt.Errorf("scan tasks: %w", err)
+	}
+
+	if err := list.validate(); err != nil {
+		return List{}, err
+	}
+	return list, nil
+}
+
+func splitTaskID(text string, position int) (string, string) {
+	if match := taskIDPattern.FindStringSubmatch(text); match != nil {
+		number, _ := strconv.Atoi(match[1])
+		return fmt.Sprintf("T%d", number), strings.TrimSpace(match[2])
+	}
+	return fmt.Sprintf("T%d", position), text
+}
+
+func isStatusKey(key string) bool {
+	return strings.EqualFold(strings.TrimSpace(key), "status")
+}
+
+// setField stores value under key. Repeated goal, tests and verification
+// values (list items under a heading task's field) are joined.
+func setField(task *Task, key string, value string) {
+	switch strings.ToLower(strings.TrimSpace(key)) {
+	case "status":
+		status := strings.ToLower(value)
+		task.Done = strings.HasPrefix(status, "done") || strings.HasPrefix(status, "complete")
+	case "goal":
+		task.Goal = joinField(task.Goal, value, " ")
+	case "file", "files", "files to touch":
+		for _, file := range strings.Split(value, ",") {
+			if file = strings.Trim(strings.TrimSpace(file), "`"); file != "" {
+				task.Files = append(task.Files, file)
+			}
+		}
+	case "test", "tests", "tests to add":
+		task.Tests = joinField(task.Tests, value, "; ")
+	case "verification", "verify":
+		task.Verification = joinField(task.Verification, value, "; ")
+	case "depends on", "depends", "dependencies", "after", "blocked by":
+		for _, match := range dependsPattern.FindAllStringSubmatch(value, -1) {
+			number, _ := strconv.Atoi(match[1])
+			if id := fmt.Sprintf("T%d", number); !slices.Contains(task.DependsOn, id) {
+				task.DependsOn = append(task.DependsOn, id)
+			}
+		}
+	}
+}
+
+func joinField(current string, value string, separator string) string {
+	switch {
+	case value == "":
+		return current
+	case current == "":
+		return value
+	default:
+		return current + separator + value
+	}
+}
+
+// ValidationError reports a task list that parsed but whose ids or
+// dependencies do not add up.
+type ValidationError struct {
+	Line   int
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Reason)
+}
+
+func (l List) validate() error {
+	lines := map[string]int{}
+	for _, task := range l.Tasks {
+		if first, ok := lines[task.ID]; ok {
+			return &ValidationError{Line: task.Line, Reason: fmt.Sprintf("duplicate task id %s (first on line %d)", task.ID, first)}
+		}
+		lines[task.ID] = task.Line
+	}
+
+	for _, task := range l.Tasks {
+		for _, dependency := range task.DependsOn {
+			if dependency == task.ID {
+				return &ValidationError{Line: task.Line, Reason: fmt.Sprintf("%s depends on itself", task.ID)}
+			}
+			if _, ok := lines[dependency]; !ok {
+				return &ValidationError{Line: task.Line, Reason: fmt.Sprintf("%s depends on unknown task %s", task.ID, dependency)}
+			}
+		}
+	}
+	return nil
+}
+
+func (l List) Find(id string) (Task, bool) {
+	id = strings.ToUpper(strings.TrimSpace(id))
+	for _, task := range l.Tasks {
+		if task.ID == id {
+			return task, true
+		}
+	}
+	return Task{}, false
+}
+
+// Blockers lists the unchecked tasks that task still waits on.
+func (l List) Blockers(task Task) []string {
+	var blockers []string
+	for _, id := range task.DependsOn {
+		if dependency, ok := l.Find(id); ok && !dependency.Done {
+			blockers = append(blockers, id)
+		}
+	}
+	return blockers
+}
+
+func (l List) Progress() Progress {
+	progress := Progress{Total: len(l.Tasks)}
+	for i, task := range l.Tasks {
+		if task.Done {
+			progress.Done++
+			continue
+		}
+		if progress.Next == nil && len(l.Blockers(task)) == 0 {
+			progress.Next = &l.Tasks[i]
+		}
+	}
+	return progress
+}
+
+func (p Progress) String() string {
+	summary := fmt.Sprintf("%d/%d tasks", p.Done, p.Total)
+	switch {
+	case p.Total > 0 && p.Done == p.Total:
+		return summary + ", all done"
+	case p.Next != nil:
+		return strings.TrimSpace(fmt.Sprintf("%s, next: %s %s", summary, p.Next.ID, p.Next.Title))
+	case p.Total > 0:
+		return summary + ", remaining tasks blocked"
+	default:
+		return summary
+	}
+}
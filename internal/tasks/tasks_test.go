@@ -0,0 +1,196 @@
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleTasks = `# Tasks: retry downloads
+
+Intro text is ignored.
+
+- [x] T1: add backoff helper
+  - Goal: exponential backoff with a cap
+  - Files: ` + "`internal/download/backoff.go`, `internal/download/backoff_test.go`" + `
+  - Tests: TestBackoffCaps
+  - Verification: go test ./internal/download/
+  - Depends on: none
+- [ ] T2: retry on 5xx
+  - **Depends on:** T1
+- [X] **T3** - log attempts
+- [ ] T4. resume partial downloads
+  - Depends on: T2, T3
+  - [ ] nested checklist items are not tasks
+
+## Notes
+- [ ] untracked follow-up
+`
+
+func TestParseReadsTasksAndFields(t *testing.T) {
+	list, err := Parse(strings.NewReader(sampleTasks))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	var ids []string
+	for _, task := range list.Tasks {
+		ids = append(ids, task.ID)
+	}
+	if want := []string{"T1", "T2", "T3", "T4", "T5"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("ids: got %v, want %v", ids, want)
+	}
+
+	first := list.Tasks[0]
+	if !first.Done || first.Title != "add backoff helper" || first.Line != 5 {
+		t.Fatalf("first task: %+v", first)
+	}
+	if first.Goal != "exponential backoff with a cap" || first.Tests != "TestBackoffCaps" || first.Verification != "go test ./internal/download/" {
+		t.Fatalf("first task fields: %+v", first)
+	}
+	if want := []string{"internal/download/backoff.go", "internal/download/backoff_test.go"}; !reflect.DeepEqual(first.Files, want) {
+		t.Fatalf("files: got %v", first.Files)
+	}
+	if len(first.DependsOn) != 0 {
+		t.Fatalf("depends on none: got %v", first.DependsOn)
+	}
+
+	if task, _ := list.Find("t3"); !task.Done || task.Title != "log attempts" {
+		t.Fatalf("T3: %+v", task)
+	}
+	if task, _ := list.Find("T4"); !reflect.DeepEqual(task.DependsOn, []string{"T2", "T3"}) {
+		t.Fatalf("T4 depends on: %v", task.DependsOn)
+	}
+}
+
+func TestProgressSkipsBlockedTasks(t *testing.T) {
+	list, err := Parse(strings.NewReader("- [ ] T1: b first\n  - Depends on: T2\n- [ ] T2: a second\n- [x] T3: done\n"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if got := list.Progress().String(); got != "1/3 tasks, next: T2 a second" {
+		t.Fatalf("progress: got %q", got)
+	}
+	if got := list.Blockers(list.Tasks[0]); !reflect.DeepEqual(got, []string{"T2"}) {
+		t.Fatalf("blockers: got %v", got)
+	}
+}
+
+func TestProgressString(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "", want: "0/0 tasks"},
+		{input: "- [x] T1: a\n- [x] T2: b\n", want: "2/2 tasks, all done"},
+		{input: "- [ ] T1: a\n  - Depends on: T2\n- [ ] T2: b\n  - Depends on: T1\n", want: "0/2 tasks, remaining tasks blocked"},
+	}
+
+	for _, tc := range tests {
+		list, err := Parse(strings.NewReader(tc.input))
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tc.input, err)
+		}
+		if got := list.Progress().String(); got != tc.want {
+			t.Fatalf("Progress(%q): got %q, want %q", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestParseRejectsBadReferences(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "- [ ] T1: a\n- [ ] T1: b\n", want: "line 2: duplicate task id T1"},
+		{input: "- [ ] T1: a\n  - Depends on: T9\n", want: "line 1: T1 depends on unknown task T9"},
+		{input: "- [ ] T1: a\n  - Depends on: T1\n", want: "line 1: T1 depends on itself"},
+	}
+
+	for _, tc := range tests {
+		_, err := Parse(strings.NewReader(tc.input))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("Parse(%q): got %v, want %q", tc.input, err, tc.want)
+		}
+	}
+}
+
+const headingTasks = `# TASKS.md — spire CLI
+Depends on: PLAN.md approved
+
+## Phase 1 — Bootstrap
+
+### Task 001 — Create repo skeleton
+**Goal:** Establish project layout.
+**Files:**
+- ` + "`go.mod`" + `
+- ` + "`cmd/spire/main.go`" + `
+**Tests:** None (content files).
+**Verification:**
+- All files exist.
+- ` + "`go build ./...`" + ` passes.
+**Status:** done
+
+---
+
+### Task 002 — CLI skeleton
+**Goal:** Implement --help and --version.
+**Depends on:** Task 001
+
+## Summary
+| 001 | skeleton |
+`
+
+func TestParseReadsHeadingTasks(t *testing.T) {
+	list, err := Parse(strings.NewReader(headingTasks))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(list.Tasks) != 2 {
+		t.Fatalf("tasks: got %+v", list.Tasks)
+	}
+
+	first := list.Tasks[0]
+	if first.ID != "T1" || first.Title != "Create repo skeleton" || !first.Done || first.Line != 6 {
+		t.Fatalf("first task: %+v", first)
+	}
+	if want := []string{"go.mod", "cmd/spire/main.go"}; !reflect.DeepEqual(first.Files, want) {
+		t.Fatalf("files: got %v", first.Files)
+	}
+	if first.Verification != "All files exist.; `go build ./...` passes." {
+		t.Fatalf("verification: got %q", first.Verification)
+	}
+
+	second := list.Tasks[1]
+	if second.ID != "T2" || second.Done || !reflect.DeepEqual(second.DependsOn, []string{"T1"}) {
+		t.Fatalf("second task: %+v", second)
+	}
+	if got := list.Progress().String(); got != "1/2 tasks, next: T2 CLI skeleton" {
+		t.Fatalf("progress: got %q", got)
+	}
+}
+
+func TestMarkDoneHeadingTasks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), Filename)
+	content := "### Task 1 — a\n**Status:** in progress\n\n### Task 2 — b\n**Goal:** g\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	for _, id := range []string{"T1", "T2"} {
+		if _, already, err := MarkDone(path, id); err != nil || already {
+			t.Fatalf("MarkDone(%s): already=%v err=%v", id, already, err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if want := "### Task 1 — a\n**Status:** done\n\n### Task 2 — b\n**Status:** done\n**Goal:** g\n"; string(data) != want {
+		t.Fatalf("content: got %q, want %q", data, want)
+	}
+}
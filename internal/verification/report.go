@@ -0,0 +1,139 @@
+package verification
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	Filename = "VERIFICATION_REPORT.md"
+
+	VerdictReady     = "READY FOR PR"
+	VerdictNeedsWork = "NEEDS WORK"
+)
+
+// headingPattern matches markdown headings with an optional section number,
+// such as `## 1. TRACEABILITY MATRIX`.
+var headingPattern = regexp.MustCompile(`^#{1,6}\s+(?:\d+[.)]\s*)?(.*?)\s*#*$`)
+
+// verdictPattern matches a verdict token at the start of a line, optionally
+// followed by a separator and a reason.
+var verdictPattern = regexp.MustCompile(`^(READY FOR PR|NEEDS WORK)\s*(?:$|[:.(|\-–—]\s*(.*)$)`)
+
+type Report struct {
+	// Verdict is VerdictReady, VerdictNeedsWork, or empty when the verdict
+	// line does not start with exactly one of them.
+	Verdict string
+	// Matrix holds the lines of the TRACEABILITY MATRIX section, without
+	// leading or trailing blank lines.
+	Matrix []string
+}
+
+// Path returns where a feature's verification report lives.
+func Path(projectRoot string, slug string) string {
+	return filepath.Join(projectRoot, "changes", slug, Filename)
+}
+
+// Load parses a feature's verification report. It returns nil when the file
+// does not exist.
+func Load(projectRoot string, slug string) (*Report, error) {
+	path := Path(projectRoot, slug)
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("open verification report %q: %w", path, err)
+	}
+	defer file.Close()
+
+	report, err := Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &report, nil
+}
+
+// Parse reads the traceability matrix and the verdict. The verdict comes from
+// the first non-blank line of the VERDICT section (or the heading itself, as
+// in `## VERDICT: NEEDS WORK`), or else the first `Verdict:` line, and only
+// counts when that line starts with READY FOR PR or NEEDS WORK.
+func Parse(r io.Reader) (Report, error) {
+	var report Report
+	section := ""
+	verdictRead := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if match := headingPattern.FindStringSubmatch(trimmed); match != nil {
+			section = strings.ToUpper(strings.Trim(match[1], "* "))
+			if !verdictRead && strings.HasPrefix(section, "VERDICT") {
+				if verdict := verdictOf(section); verdict != "" {
+					report.Verdict = verdict
+					verdictRead = true
+				}
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(section, "TRACEABILITY MATRIX"):
+			report.Matrix = append(report.Matrix, line)
+		case !verdictRead && trimmed != "" && (strings.HasPrefix(section, "VERDICT") || strings.HasPrefix(strings.ToUpper(strings.Trim(trimmed, "*- ")), "VERDICT:")):
+			report.Verdict = verdictOf(trimmed)
+			verdictRead = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Report{}, fmt.Errorf("scan verification report: %w", err)
+	}
+
+	report.Matrix = trimBlankLines(report.Matrix)
+	return report, nil
+}
+
+func (r Report) Ready() bool {
+	return r.Verdict == VerdictReady
+}
+
+// verdictOf reads the verdict a line starts with, after markdown emphasis,
+// list markers and a `Verdict:` label. "Not READY FOR PR", "fix tests, then
+// READY FOR PR" and "READY FOR PR | NEEDS WORK" have none.
+func verdictOf(line string) string {
+	text := strings.ToUpper(strings.NewReplacer("*", "", "`", "", "_", "").Replace(line))
+	text = strings.TrimLeft(text, "-+># \t")
+	if rest, ok := strings.CutPrefix(text, "VERDICT"); ok {
+		text = strings.TrimLeft(rest, ":-–— \t")
+	}
+
+	match := verdictPattern.FindStringSubmatch(text)
+	if match == nil {
+		return ""
+	}
+	other := VerdictNeedsWork
+	if match[1] == VerdictNeedsWork {
+		other = VerdictReady
+	}
+	if strings.Contains(match[2], other) {
+		return ""
+	}
+	return match[1]
+}
+
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
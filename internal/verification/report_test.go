@@ -0,0 +1,45 @@
+package verification
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseReadsMatrixAndVerdict(t *testing.T) {
+	input := "# Verification Report\n\n## 1. TRACEABILITY MATRIX\n\nAC-1 | implemented in retry.go:12 | tested by retry_test.go:TestRetry | PASS\nAC-2 | implemented in resume.go:40 | tested by resume_test.go:TestResume | PASS\n\n## 2. COMMANDS RUN\ngo test ./...\n\n## 5. VERDICT\n\n**READY FOR PR**\n"
+	report, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	if !report.Ready() {
+		t.Fatalf("verdict: got %q", report.Verdict)
+	}
+	if len(report.Matrix) != 2 || !strings.HasPrefix(report.Matrix[1], "AC-2") {
+		t.Fatalf("matrix: %q", report.Matrix)
+	}
+}
+
+func TestParseVerdictForms(t *testing.T) {
+	for input, want := range map[string]string{
+		"## VERDICT: NEEDS WORK\n- add resume test\n":               VerdictNeedsWork,
+		"Verdict: READY FOR PR\n":                                   VerdictReady,
+		"## VERDICT\nREADY FOR PR | NEEDS WORK\n":                   "",
+		"## SELF-REVIEW\nready for pr soon\n":                       "",
+		"## VERDICT\n- READY FOR PR: all ACs pass\n":                VerdictReady,
+		"## VERDICT\nNEEDS WORK (AC-2 untested)\n":                  VerdictNeedsWork,
+		"## VERDICT\nNot READY FOR PR: AC-2 fails\n":                "",
+		"## VERDICT\nfix tests, then READY FOR PR\n":                "",
+		"## VERDICT\nNEEDS WORK\nREADY FOR PR\n":                    VerdictNeedsWork,
+		"## VERDICT\nREADY FOR PR | NEEDS WORK\n**READY FOR PR**\n": "",
+		"Verdict: not READY FOR PR\n":                               "",
+	} {
+		report, err := Parse(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", input, err)
+		}
+		if report.Verdict != want {
+			t.Fatalf("Parse(%q) verdict: got %q, want %q", input, report.Verdict, want)
+		}
+	}
+}
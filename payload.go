@@ -0,0 +1,17 @@
+package spire
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed all:methodology
+var methodologyFiles embed.FS
+
+// PayloadVersion identifies the embedded methodology snapshot. Release builds
+// set it with -ldflags "-X 'opencode-spire.PayloadVersion=<version>'".
+var PayloadVersion = "dev"
+
+func Methodology() (fs.FS, error) {
+	return fs.Sub(methodologyFiles, "methodology")
+}